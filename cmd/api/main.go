@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 	_ "time-tracker/docs"
 	"time-tracker/internal/config"
 	repository "time-tracker/internal/db/sqlc"
@@ -9,6 +11,13 @@ import (
 	"time-tracker/internal/server"
 	"time-tracker/internal/service"
 	"time-tracker/pkg/database"
+	"time-tracker/pkg/fieldcrypt"
+	"time-tracker/pkg/jwt"
+	"time-tracker/pkg/notify"
+	"time-tracker/pkg/oidc"
+	"time-tracker/pkg/peopleinfo"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // @title Time Tracker API
@@ -16,24 +25,121 @@ import (
 // @description API Server for Time Tracker Application
 // @host localhost:8000
 // @BasePath /api
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Either "Bearer <JWT>" or "Bearer <API key>". API keys may also be sent in the X-API-Key header.
 func main() {
 	cfg, err := config.NewConfig()
 	if err != nil {
 		log.Fatalf("error loading env variables: %s", err.Error())
 	}
 
-	pgxPool, err := database.NewPostgresDB(cfg)
+	pgxPool, err := database.NewPostgresDB(cfg.DBSource)
 	if err != nil {
 		log.Fatalf("error loading env variables: %s", err.Error())
 	}
 	defer pgxPool.Close()
 
-	newRepository := repository.New(pgxPool)
-	newService := service.NewService(newRepository)
+	systemPool, err := database.NewPostgresDB(cfg.DBSystemSource)
+	if err != nil {
+		log.Fatalf("error connecting to the database as the system role: %s", err.Error())
+	}
+	defer systemPool.Close()
+
+	var budgetNotifier service.BudgetNotifier = notify.LogNotifier{}
+	if cfg.BudgetWebhookURL != "" {
+		budgetNotifier = notify.NewWebhookNotifier(cfg.BudgetWebhookURL)
+	}
+
+	taskSettings := service.TaskSettings{
+		MaxBackdate: cfg.TaskMaxBackdate,
+		ResumeGap:   cfg.TaskResumeGap,
+	}
+
+	verifier, err := newJWTVerifier(cfg)
+	if err != nil {
+		log.Fatalf("error loading JWT keys: %s", err.Error())
+	}
+	if !verifier.Enabled() {
+		log.Printf("no JWT key configured, only API keys are accepted")
+	}
+
+	var identityProvider service.IdentityProvider
+	if cfg.OIDCIssuer != "" {
+		identityProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+			Leeway:       cfg.JWTLeeway,
+		})
+	}
+	sessions := service.SessionSettings{
+		Secret:   []byte(cfg.JWTSecret),
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+		TTL:      cfg.SessionTTL,
+	}
+
+	var peopleInfo service.PeopleInfoProvider
+	if cfg.PeopleInfoURL != "" {
+		peopleInfo = peopleinfo.NewClient(peopleinfo.Config{
+			BaseURL:          cfg.PeopleInfoURL,
+			Timeout:          cfg.PeopleInfoTimeout,
+			MaxRetries:       cfg.PeopleInfoMaxRetries,
+			RetryDelay:       cfg.PeopleInfoRetryDelay,
+			FailureThreshold: cfg.PeopleInfoFailureThreshold,
+			OpenTimeout:      cfg.PeopleInfoOpenTimeout,
+		})
+	}
+
+	keyring, err := fieldcrypt.ParseKeyring(cfg.PassportEncryptionKeys, cfg.PassportIndexKeys)
+	if err != nil {
+		log.Fatalf("error loading passport keys: %s", err.Error())
+	}
+
+	storeConfig := repository.StoreConfig{
+		IsoLevel:   pgx.TxIsoLevel(cfg.DBTxIsolation),
+		MaxRetries: cfg.DBTxMaxRetries,
+		RetryDelay: cfg.DBTxRetryDelay,
+	}
+	newRepository := repository.NewStore(pgxPool, storeConfig)
+	systemRepository := repository.NewStore(systemPool, storeConfig)
+	newService := service.NewService(newRepository, systemRepository, budgetNotifier, taskSettings, cfg.IdempotencyKeyTTL, verifier, identityProvider, sessions, keyring, peopleInfo)
 	newHandler := handler.NewHandler(newService)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go newService.IBudgetService.WatchRunningBudgets(ctx, cfg.BudgetCheckInterval)
+	go newService.IPomodoroService.WatchPomodoros(ctx, cfg.PomodoroCheckInterval)
+	go newService.IIdempotencyService.WatchIdempotencyKeys(ctx, cfg.IdempotencyCleanupInterval)
+
 	srv := new(server.Server)
 	if err := srv.Run(cfg.ServerPort, newHandler); err != nil {
 		log.Fatalf("error occured while running http server: %s", err.Error())
 	}
 }
+
+func newJWTVerifier(cfg *config.Config) (*jwt.Verifier, error) {
+	verifier := &jwt.Verifier{
+		HMACSecret: []byte(cfg.JWTSecret),
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		Leeway:     cfg.JWTLeeway,
+	}
+
+	if cfg.JWTPublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if verifier.RSAKey, err = jwt.ParseRSAPublicKeyPEM(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return verifier, nil
+}
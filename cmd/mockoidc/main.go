@@ -0,0 +1,269 @@
+// Command mockoidc is a minimal OpenID provider for developing and testing the
+// OIDC login locally. It signs in every user without asking for credentials:
+// the identity comes from the flags, or from the sub and email query
+// parameters of the authorization request.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"time-tracker/pkg/jwt"
+	"time-tracker/pkg/oidc"
+)
+
+const (
+	keyID        = "mock"
+	codeLifetime = time.Minute
+	tokenLife    = 5 * time.Minute
+)
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	subject       string
+	email         string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	subject      string
+	email        string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+type idTokenClaims struct {
+	jwt.Claims
+	Nonce         string `json:"nonce,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL, as reached by the API and the browser")
+	clientID := flag.String("client-id", "time-tracker", "accepted client id")
+	clientSecret := flag.String("client-secret", "secret", "accepted client secret")
+	subject := flag.String("subject", "mock-user", "default subject of signed in users")
+	email := flag.String("email", "", "default email of signed in users")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("error generating signing key: %s", err.Error())
+	}
+
+	p := &provider{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		subject:      *subject,
+		email:        *email,
+		key:          key,
+		codes:        make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+
+	log.Printf("mock OpenID provider %s listening on %s", p.issuer, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("error occured while running http server: %s", err.Error())
+	}
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{jwt.RS256},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (p *provider) jwks(w http.ResponseWriter, r *http.Request) {
+	publicKey := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": jwt.RS256,
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize approves every request and redirects back with a code.
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != p.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+
+	callback := redirectURI.Query()
+	callback.Set("state", query.Get("state"))
+	switch {
+	case query.Get("response_type") != "code":
+		callback.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		callback.Set("error", "invalid_request")
+		callback.Set("error_description", "PKCE with S256 is required")
+	case !strings.Contains(" "+query.Get("scope")+" ", " openid "):
+		callback.Set("error", "invalid_scope")
+	default:
+		subject, email := p.subject, p.email
+		if query.Has("sub") {
+			subject = query.Get("sub")
+		}
+		if query.Has("email") {
+			email = query.Get("email")
+		}
+
+		code := randomString()
+		p.mu.Lock()
+		p.codes[code] = authorization{
+			clientID:      p.clientID,
+			redirectURI:   redirectURI.String(),
+			codeChallenge: query.Get("code_challenge"),
+			nonce:         query.Get("nonce"),
+			subject:       subject,
+			email:         email,
+			expiresAt:     time.Now().Add(codeLifetime),
+		}
+		p.mu.Unlock()
+
+		callback.Set("code", code)
+		log.Printf("signed in %q <%s>", subject, email)
+	}
+
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		tokenError(w, http.StatusMethodNotAllowed, "invalid_request", "POST required")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	clientID, clientSecret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	if user, password, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(user)
+		clientSecret, _ = url.QueryUnescape(password)
+	}
+	if clientID != p.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.clientSecret)) != 1 {
+		tokenError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	switch {
+	case !ok || time.Now().After(auth.expiresAt):
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "unknown or expired code")
+		return
+	case auth.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri mismatch")
+		return
+	case oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != auth.codeChallenge:
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "code_verifier mismatch")
+		return
+	}
+
+	now := time.Now()
+	idToken, err := jwt.SignRS256(idTokenClaims{
+		Claims: jwt.Claims{
+			Subject:   auth.subject,
+			Issuer:    p.issuer,
+			Audience:  jwt.Audience{auth.clientID},
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(tokenLife).Unix(),
+		},
+		Nonce:         auth.nonce,
+		Email:         auth.email,
+		EmailVerified: auth.email != "",
+	}, p.key, keyID)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenLife.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code, description string) {
+	body := map[string]string{"error": code}
+	if description != "" {
+		body["error_description"] = description
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("error writing response: %v", err)
+	}
+}
+
+func randomString() string {
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		log.Fatalf("error generating random value: %s", err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(random)
+}
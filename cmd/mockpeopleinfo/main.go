@@ -0,0 +1,133 @@
+// Command mockpeopleinfo is a stub of the people info API for developing and
+// testing the creation of users locally. It answers every passport with
+// details derived from its number, except the numbers given with -unknown.
+// The -delay and -fail-rate flags exercise the timeout, retries and circuit
+// breaker of the client.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	passportSeriePattern  = regexp.MustCompile(`^\d{4}$`)
+	passportNumberPattern = regexp.MustCompile(`^\d{6}$`)
+)
+
+var (
+	surnames    = []string{"Ivanov", "Petrov", "Sidorov", "Smirnov", "Kuznetsov", "Popov"}
+	names       = []string{"Ivan", "Pyotr", "Sergey", "Alexey", "Dmitry", "Nikolay"}
+	patronymics = []string{"Ivanovich", "Petrovich", "Sergeevich", "Alexeevich", "Dmitrievich", ""}
+	streets     = []string{"Lenina", "Sadovaya", "Gagarina", "Mira", "Pushkina", "Tverskaya"}
+)
+
+type people struct {
+	Surname    string  `json:"surname"`
+	Name       string  `json:"name"`
+	Patronymic *string `json:"patronymic,omitempty"`
+	Address    string  `json:"address"`
+}
+
+type server struct {
+	delay    time.Duration
+	failRate float64
+	unknown  map[string]bool
+}
+
+func main() {
+	addr := flag.String("addr", ":9100", "listen address")
+	delay := flag.Duration("delay", 0, "time waited before answering")
+	failRate := flag.Float64("fail-rate", 0, "share of requests answered with 500 Internal Server Error, from 0 to 1")
+	unknown := flag.String("unknown", "0000 000000", "comma-separated passports answered with 404 Not Found")
+	flag.Parse()
+
+	s := &server{
+		delay:    *delay,
+		failRate: *failRate,
+		unknown:  make(map[string]bool),
+	}
+	for _, passport := range strings.Split(*unknown, ",") {
+		if passport = strings.TrimSpace(passport); passport != "" {
+			s.unknown[passport] = true
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", s.info)
+
+	log.Printf("mock people info API listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("error occured while running http server: %s", err.Error())
+	}
+}
+
+func (s *server) info(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "GET required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	select {
+	case <-r.Context().Done():
+		return
+	case <-time.After(s.delay):
+	}
+
+	if rand.Float64() < s.failRate {
+		log.Printf("failing %s", r.URL.RawQuery)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	passportSerie, passportNumber := query.Get("passportSerie"), query.Get("passportNumber")
+	if !passportSeriePattern.MatchString(passportSerie) || !passportNumberPattern.MatchString(passportNumber) {
+		http.Error(w, "invalid passportSerie or passportNumber", http.StatusBadRequest)
+		return
+	}
+
+	passport := passportSerie + " " + passportNumber
+	if s.unknown[passport] {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	person := newPeople(passport)
+	log.Printf("found %s %s for %s", person.Name, person.Surname, passport)
+	writeJSON(w, http.StatusOK, person)
+}
+
+// newPeople returns the details of a passport, always the same for the same
+// passport.
+func newPeople(passport string) people {
+	hash := fnv.New32a()
+	hash.Write([]byte(passport))
+	seed := int(hash.Sum32())
+
+	person := people{
+		Surname: surnames[seed%len(surnames)],
+		Name:    names[seed/7%len(names)],
+		Address: "ul. " + streets[seed/49%len(streets)] + ", d. " + passport[len(passport)-2:],
+	}
+	if patronymic := patronymics[seed/343%len(patronymics)]; patronymic != "" {
+		person.Patronymic = &patronymic
+	}
+	return person
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("error writing response: %v", err)
+	}
+}
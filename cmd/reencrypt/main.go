@@ -0,0 +1,128 @@
+// Command reencrypt brings the stored passport numbers up to date with the
+// configured keys: it encrypts the plain values written before encryption,
+// re-encrypts values sealed with an older key and rebuilds blind indexes made
+// with an older index key. With -decrypt it writes the plain values back
+// instead, before rolling the encryption migration back.
+//
+// It uses the same environment as the API and may run while the API serves
+// requests.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time-tracker/internal/config"
+	repository "time-tracker/internal/db/sqlc"
+	"time-tracker/pkg/database"
+	"time-tracker/pkg/fieldcrypt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func main() {
+	pageSize := flag.Int("batch", 500, "users read per query")
+	decrypt := flag.Bool("decrypt", false, "store passport numbers in plain text again")
+	dryRun := flag.Bool("dry-run", false, "only count the users to update")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("error loading env variables: %s", err.Error())
+	}
+
+	keyring, err := fieldcrypt.ParseKeyring(cfg.PassportEncryptionKeys, cfg.PassportIndexKeys)
+	if err != nil {
+		log.Fatalf("error loading passport keys: %s", err.Error())
+	}
+
+	pgxPool, err := database.NewPostgresDB(cfg.DBSystemSource)
+	if err != nil {
+		log.Fatalf("error connecting to the database: %s", err.Error())
+	}
+	defer pgxPool.Close()
+
+	// The system role sees every organization.
+	ctx := context.Background()
+	queries := repository.New(pgxPool)
+
+	after := pgtype.UUID{Valid: true}
+	var scanned, updated int
+	for {
+		users, err := queries.GetUsersPage(ctx, repository.GetUsersPageParams{
+			AfterUuid: after,
+			PageSize:  int32(*pageSize),
+		})
+		if err != nil {
+			log.Fatalf("error reading users: %s", err.Error())
+		}
+		if len(users) == 0 {
+			break
+		}
+		after = users[len(users)-1].Uuid
+
+		for _, user := range users {
+			scanned++
+
+			params, ok, err := passportUpdate(keyring, user, *decrypt)
+			if err != nil {
+				log.Fatalf("user %x: %s", user.Uuid.Bytes, err.Error())
+			}
+			if !ok {
+				continue
+			}
+
+			updated++
+			if *dryRun {
+				continue
+			}
+			if err := queries.SetUserPassportNumber(ctx, params); err != nil {
+				log.Fatalf("user %x: error saving passport number: %s", user.Uuid.Bytes, err.Error())
+			}
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d users scanned, %d to update", scanned, updated)
+		return
+	}
+	log.Printf("%d users scanned, %d updated", scanned, updated)
+}
+
+// passportUpdate returns the passport columns to store for user, and false
+// when they are already up to date or the user is anonymized.
+func passportUpdate(keyring *fieldcrypt.Keyring, user repository.User, decrypt bool) (repository.SetUserPassportNumberParams, bool, error) {
+	params := repository.SetUserPassportNumberParams{UserUuid: user.Uuid}
+	if !user.PassportNumber.Valid && !user.PassportNumberCiphertext.Valid {
+		return params, false, nil
+	}
+
+	passportNumber := user.PassportNumber.String
+	if user.PassportNumberCiphertext.Valid {
+		var err error
+		if passportNumber, err = keyring.Decrypt(user.PassportNumberCiphertext.String); err != nil {
+			return params, false, err
+		}
+	}
+
+	if decrypt {
+		if !user.PassportNumberCiphertext.Valid {
+			return params, false, nil
+		}
+		params.PassportNumber = pgtype.Text{String: passportNumber, Valid: true}
+		return params, true, nil
+	}
+
+	index := keyring.BlindIndex(passportNumber)
+	if !user.PassportNumber.Valid && keyring.IsCurrent(user.PassportNumberCiphertext.String) && user.PassportNumberIndex.String == index {
+		return params, false, nil
+	}
+
+	ciphertext, err := keyring.Encrypt(passportNumber)
+	if err != nil {
+		return params, false, err
+	}
+	params.PassportNumberCiphertext = pgtype.Text{String: ciphertext, Valid: true}
+	params.PassportNumberIndex = pgtype.Text{String: index, Valid: true}
+	return params, true, nil
+}
@@ -0,0 +1,14 @@
+#!/bin/sh
+# Creates the login roles of the application when the database container is
+# initialized, with the passwords of the environment. The migrations grant them
+# their privileges.
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+    -v app_password="$DB_APP_PASSWORD" \
+    -v system_password="$DB_SYSTEM_PASSWORD" <<'EOSQL'
+CREATE ROLE time_tracker_app LOGIN PASSWORD :'app_password'
+    NOSUPERUSER NOCREATEDB NOCREATEROLE NOBYPASSRLS;
+CREATE ROLE time_tracker_system LOGIN PASSWORD :'system_password'
+    NOSUPERUSER NOCREATEDB NOCREATEROLE BYPASSRLS;
+EOSQL
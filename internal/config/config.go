@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -11,6 +12,49 @@ import (
 type Config struct {
 	DBSource   string `env:"DB_SOURCE,required"`
 	ServerPort string `env:"SERVER_PORT,required"`
+
+	// DBSystemSource connects as a role bypassing row-level security, for
+	// authentication and background jobs that span organizations.
+	DBSystemSource string `env:"DB_SYSTEM_SOURCE,required"`
+
+	DBTxIsolation  string        `env:"DB_TX_ISOLATION" envDefault:"read committed"`
+	DBTxMaxRetries int           `env:"DB_TX_MAX_RETRIES" envDefault:"3"`
+	DBTxRetryDelay time.Duration `env:"DB_TX_RETRY_DELAY" envDefault:"50ms"`
+
+	BudgetWebhookURL    string        `env:"BUDGET_WEBHOOK_URL"`
+	BudgetCheckInterval time.Duration `env:"BUDGET_CHECK_INTERVAL" envDefault:"1m"`
+
+	PomodoroCheckInterval time.Duration `env:"POMODORO_CHECK_INTERVAL" envDefault:"15s"`
+
+	TaskMaxBackdate time.Duration `env:"TASK_MAX_BACKDATE" envDefault:"24h"`
+	TaskResumeGap   time.Duration `env:"TASK_RESUME_GAP" envDefault:"5m"`
+
+	IdempotencyKeyTTL          time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+	IdempotencyCleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1h"`
+
+	JWTSecret        string        `env:"AUTH_JWT_SECRET"`
+	JWTPublicKeyFile string        `env:"AUTH_JWT_PUBLIC_KEY_FILE"`
+	JWTIssuer        string        `env:"AUTH_JWT_ISSUER"`
+	JWTAudience      string        `env:"AUTH_JWT_AUDIENCE"`
+	JWTLeeway        time.Duration `env:"AUTH_JWT_LEEWAY" envDefault:"1m"`
+	SessionTTL       time.Duration `env:"AUTH_SESSION_TTL" envDefault:"8h"`
+
+	// The first key of each list is current; the others are only read.
+	PassportEncryptionKeys []string `env:"PASSPORT_ENCRYPTION_KEYS,required"`
+	PassportIndexKeys      []string `env:"PASSPORT_INDEX_KEYS,required"`
+
+	OIDCIssuer       string   `env:"OIDC_ISSUER"`
+	OIDCClientID     string   `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string   `env:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string   `env:"OIDC_REDIRECT_URL"`
+	OIDCScopes       []string `env:"OIDC_SCOPES" envDefault:"openid email profile" envSeparator:" "`
+
+	PeopleInfoURL              string        `env:"PEOPLE_INFO_URL"`
+	PeopleInfoTimeout          time.Duration `env:"PEOPLE_INFO_TIMEOUT" envDefault:"3s"`
+	PeopleInfoMaxRetries       int           `env:"PEOPLE_INFO_MAX_RETRIES" envDefault:"2"`
+	PeopleInfoRetryDelay       time.Duration `env:"PEOPLE_INFO_RETRY_DELAY" envDefault:"200ms"`
+	PeopleInfoFailureThreshold int           `env:"PEOPLE_INFO_FAILURE_THRESHOLD" envDefault:"5"`
+	PeopleInfoOpenTimeout      time.Duration `env:"PEOPLE_INFO_OPEN_TIMEOUT" envDefault:"30s"`
 }
 
 func NewConfig() (*Config, error) {
@@ -26,5 +70,20 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("error loading .env file: %w", err)
 	}
 
+	switch cfg.DBTxIsolation {
+	case "read committed", "repeatable read", "serializable":
+	default:
+		return nil, fmt.Errorf("invalid DB_TX_ISOLATION: %q", cfg.DBTxIsolation)
+	}
+
+	if cfg.OIDCIssuer != "" {
+		if cfg.OIDCClientID == "" || cfg.OIDCRedirectURL == "" {
+			return nil, fmt.Errorf("OIDC_ISSUER requires OIDC_CLIENT_ID and OIDC_REDIRECT_URL")
+		}
+		if cfg.JWTSecret == "" {
+			return nil, fmt.Errorf("OIDC_ISSUER requires AUTH_JWT_SECRET to sign sessions")
+		}
+	}
+
 	return cfg, nil
 }
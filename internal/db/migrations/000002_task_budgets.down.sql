@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS set_task_budgets_updated_at ON task_budgets;
+
+DROP TABLE IF EXISTS task_budgets;
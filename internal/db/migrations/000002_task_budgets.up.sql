@@ -0,0 +1,15 @@
+CREATE TABLE task_budgets (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    estimate_minutes INTEGER NOT NULL CHECK (estimate_minutes > 0),
+    notified_threshold INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    UNIQUE (user_uuid, name)
+);
+
+CREATE TRIGGER set_task_budgets_updated_at
+BEFORE UPDATE ON task_budgets
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+ALTER TABLE task_histories
+    DROP COLUMN IF EXISTS interrupted,
+    DROP COLUMN IF EXISTS mode;
+
+DROP INDEX IF EXISTS tasks_planned_end_time_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS planned_end_time,
+    DROP COLUMN IF EXISTS mode;
+
+DROP TRIGGER IF EXISTS set_pomodoro_settings_updated_at ON pomodoro_settings;
+
+DROP TABLE IF EXISTS pomodoro_settings;
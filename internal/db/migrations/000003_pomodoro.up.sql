@@ -0,0 +1,25 @@
+CREATE TABLE pomodoro_settings (
+    user_uuid UUID PRIMARY KEY REFERENCES users(uuid) ON DELETE CASCADE,
+    focus_minutes INTEGER NOT NULL DEFAULT 25 CHECK (focus_minutes > 0),
+    short_break_minutes INTEGER NOT NULL DEFAULT 5 CHECK (short_break_minutes > 0),
+    long_break_minutes INTEGER NOT NULL DEFAULT 15 CHECK (long_break_minutes > 0),
+    cycles_before_long_break INTEGER NOT NULL DEFAULT 4 CHECK (cycles_before_long_break > 0),
+    auto_start_break BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL
+);
+
+CREATE TRIGGER set_pomodoro_settings_updated_at
+BEFORE UPDATE ON pomodoro_settings
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE tasks
+    ADD COLUMN mode VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (mode IN ('regular', 'focus', 'break')),
+    ADD COLUMN planned_end_time TIMESTAMPTZ;
+
+CREATE INDEX tasks_planned_end_time_idx ON tasks (planned_end_time) WHERE planned_end_time IS NOT NULL;
+
+ALTER TABLE task_histories
+    ADD COLUMN mode VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (mode IN ('regular', 'focus', 'break')),
+    ADD COLUMN interrupted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,14 @@
+CREATE TABLE work_schedules (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    monday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (monday_minutes BETWEEN 0 AND 1440),
+    tuesday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (tuesday_minutes BETWEEN 0 AND 1440),
+    wednesday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (wednesday_minutes BETWEEN 0 AND 1440),
+    thursday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (thursday_minutes BETWEEN 0 AND 1440),
+    friday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (friday_minutes BETWEEN 0 AND 1440),
+    saturday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (saturday_minutes BETWEEN 0 AND 1440),
+    sunday_minutes INTEGER NOT NULL DEFAULT 0 CHECK (sunday_minutes BETWEEN 0 AND 1440),
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    UNIQUE (user_uuid, effective_from)
+);
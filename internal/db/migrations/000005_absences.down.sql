@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS absence_balances;
+
+DROP INDEX IF EXISTS absences_user_uuid_dates_idx;
+
+DROP TABLE IF EXISTS absences;
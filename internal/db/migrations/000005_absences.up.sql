@@ -0,0 +1,22 @@
+CREATE TABLE absences (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('vacation', 'sick_leave', 'personal', 'unpaid')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    half_day_start BOOLEAN NOT NULL DEFAULT FALSE,
+    half_day_end BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX absences_user_uuid_dates_idx ON absences (user_uuid, start_date, end_date);
+
+CREATE TABLE absence_balances (
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('vacation', 'sick_leave', 'personal', 'unpaid')),
+    allowance_days DOUBLE PRECISION NOT NULL CHECK (allowance_days >= 0),
+    PRIMARY KEY (user_uuid, year, type)
+);
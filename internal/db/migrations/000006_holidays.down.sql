@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_holiday_calendars;
+
+DROP TABLE IF EXISTS holidays;
+
+DROP TRIGGER IF EXISTS set_holiday_calendars_updated_at ON holiday_calendars;
+
+DROP TABLE IF EXISTS holiday_calendars;
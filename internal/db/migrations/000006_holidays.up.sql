@@ -0,0 +1,24 @@
+CREATE TABLE holiday_calendars (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    region VARCHAR(100),
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL
+);
+
+CREATE TRIGGER set_holiday_calendars_updated_at
+BEFORE UPDATE ON holiday_calendars
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE holidays (
+    calendar_uuid UUID NOT NULL REFERENCES holiday_calendars(uuid) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (calendar_uuid, day)
+);
+
+CREATE TABLE user_holiday_calendars (
+    user_uuid UUID PRIMARY KEY REFERENCES users(uuid) ON DELETE CASCADE,
+    calendar_uuid UUID NOT NULL REFERENCES holiday_calendars(uuid) ON DELETE CASCADE
+);
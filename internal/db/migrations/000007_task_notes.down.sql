@@ -0,0 +1,6 @@
+ALTER TABLE task_histories
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS description;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS description;
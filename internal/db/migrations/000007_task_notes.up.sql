@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    ADD COLUMN description TEXT;
+
+ALTER TABLE task_histories
+    ADD COLUMN description TEXT,
+    ADD COLUMN notes TEXT;
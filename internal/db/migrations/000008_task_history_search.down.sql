@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS task_histories_search_vector_idx;
+
+ALTER TABLE task_histories
+    DROP COLUMN IF EXISTS search_vector;
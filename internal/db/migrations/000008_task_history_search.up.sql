@@ -0,0 +1,10 @@
+-- The russian configuration stems Cyrillic words with the Russian stemmer and
+-- Latin words with the English one, so it covers mixed Russian/English text.
+ALTER TABLE task_histories
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', name), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(description, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(notes, '')), 'C')
+    ) STORED;
+
+CREATE INDEX task_histories_search_vector_idx ON task_histories USING GIN (search_vector);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS task_histories_name_trgm_idx;
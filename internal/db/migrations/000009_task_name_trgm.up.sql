@@ -0,0 +1,3 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX task_histories_name_trgm_idx ON task_histories USING GIN (name gin_trgm_ops);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS task_histories_user_uuid_end_time_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS notes;
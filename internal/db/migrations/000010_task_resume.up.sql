@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN notes TEXT;
+
+CREATE INDEX task_histories_user_uuid_end_time_idx ON task_histories (user_uuid, end_time DESC);
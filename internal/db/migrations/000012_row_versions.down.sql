@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS set_task_histories_version ON task_histories;
+
+DROP TRIGGER IF EXISTS set_users_version ON users;
+
+DROP FUNCTION IF EXISTS increment_version_column;
+
+ALTER TABLE task_histories DROP COLUMN IF EXISTS version;
+
+ALTER TABLE users DROP COLUMN IF EXISTS version;
@@ -0,0 +1,21 @@
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE task_histories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_users_version
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION increment_version_column();
+
+CREATE TRIGGER set_task_histories_version
+BEFORE UPDATE ON task_histories
+FOR EACH ROW
+EXECUTE FUNCTION increment_version_column();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS api_keys_created_by_idx;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,9 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS role;
+
+DROP TABLE IF EXISTS team_holiday_calendars;
+
+DROP INDEX IF EXISTS users_manager_uuid_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS manager_uuid,
+    DROP COLUMN IF EXISTS role;
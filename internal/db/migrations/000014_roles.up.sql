@@ -0,0 +1,15 @@
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(10) NOT NULL DEFAULT 'employee' CHECK (role IN ('admin', 'manager', 'employee')),
+    ADD COLUMN manager_uuid UUID REFERENCES users(uuid) ON DELETE SET NULL CHECK (manager_uuid <> uuid);
+
+CREATE INDEX users_manager_uuid_idx ON users (manager_uuid);
+
+-- The holiday calendar of the team of a manager, used for the members without
+-- a calendar of their own.
+CREATE TABLE team_holiday_calendars (
+    manager_uuid UUID PRIMARY KEY REFERENCES users(uuid) ON DELETE CASCADE,
+    calendar_uuid UUID NOT NULL REFERENCES holiday_calendars(uuid) ON DELETE CASCADE
+);
+
+ALTER TABLE api_keys
+    ADD COLUMN role VARCHAR(10) NOT NULL DEFAULT 'employee' CHECK (role IN ('admin', 'manager', 'employee'));
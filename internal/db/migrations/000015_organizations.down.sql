@@ -0,0 +1,86 @@
+DROP POLICY IF EXISTS tenant_isolation ON holidays;
+ALTER TABLE holidays NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE holidays DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON idempotency_keys;
+ALTER TABLE idempotency_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON team_holiday_calendars;
+ALTER TABLE team_holiday_calendars NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE team_holiday_calendars DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON user_holiday_calendars;
+ALTER TABLE user_holiday_calendars NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_holiday_calendars DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON absence_balances;
+ALTER TABLE absence_balances NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE absence_balances DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON absences;
+ALTER TABLE absences NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE absences DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON work_schedules;
+ALTER TABLE work_schedules NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE work_schedules DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON pomodoro_settings;
+ALTER TABLE pomodoro_settings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE pomodoro_settings DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON task_budgets;
+ALTER TABLE task_budgets NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_budgets DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON task_histories;
+ALTER TABLE task_histories NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_histories DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON tasks;
+ALTER TABLE tasks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE tasks DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON api_keys;
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE api_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON holiday_calendars;
+ALTER TABLE holiday_calendars NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE holiday_calendars DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON organizations;
+ALTER TABLE organizations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE organizations DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS current_organization_uuid();
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS organization_uuid;
+
+ALTER TABLE holiday_calendars
+    DROP CONSTRAINT IF EXISTS holiday_calendars_organization_uuid_name_key,
+    DROP COLUMN IF EXISTS organization_uuid,
+    ADD CONSTRAINT holiday_calendars_name_key UNIQUE (name);
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_organization_uuid_passport_number_key,
+    DROP COLUMN IF EXISTS organization_uuid,
+    ADD CONSTRAINT users_passport_number_key UNIQUE (passport_number);
+
+DROP TABLE IF EXISTS organizations;
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    REVOKE USAGE, SELECT ON SEQUENCES FROM time_tracker_app, time_tracker_system;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM time_tracker_app, time_tracker_system;
+
+-- Roles are shared by every database of the cluster, so they are only dropped
+-- once their privileges here are revoked.
+DROP OWNED BY time_tracker_app, time_tracker_system;
+DROP ROLE IF EXISTS time_tracker_app;
+DROP ROLE IF EXISTS time_tracker_system;
@@ -0,0 +1,137 @@
+CREATE TABLE organizations (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL
+);
+
+-- Existing data moves to a default organization.
+INSERT INTO organizations (name) VALUES ('Default');
+
+ALTER TABLE users ADD COLUMN organization_uuid UUID REFERENCES organizations(uuid) ON DELETE CASCADE;
+UPDATE users SET organization_uuid = (SELECT uuid FROM organizations WHERE name = 'Default');
+ALTER TABLE users
+    ALTER COLUMN organization_uuid SET NOT NULL,
+    DROP CONSTRAINT users_passport_number_key,
+    ADD CONSTRAINT users_organization_uuid_passport_number_key UNIQUE (organization_uuid, passport_number);
+
+ALTER TABLE holiday_calendars ADD COLUMN organization_uuid UUID REFERENCES organizations(uuid) ON DELETE CASCADE;
+UPDATE holiday_calendars SET organization_uuid = (SELECT uuid FROM organizations WHERE name = 'Default');
+ALTER TABLE holiday_calendars
+    ALTER COLUMN organization_uuid SET NOT NULL,
+    DROP CONSTRAINT holiday_calendars_name_key,
+    ADD CONSTRAINT holiday_calendars_organization_uuid_name_key UNIQUE (organization_uuid, name);
+
+ALTER TABLE api_keys ADD COLUMN organization_uuid UUID REFERENCES organizations(uuid) ON DELETE CASCADE;
+UPDATE api_keys SET organization_uuid = (SELECT uuid FROM organizations WHERE name = 'Default');
+ALTER TABLE api_keys ALTER COLUMN organization_uuid SET NOT NULL;
+
+-- Row-level security is a second line of defense behind the tenant filters of
+-- the queries. The application sets app.organization_uuid on every connection
+-- it acquires for a tenant; connections without it see no rows. Requests run
+-- as time_tracker_app, which is subject to the policies. Authentication and
+-- background jobs work across organizations as time_tracker_system, which
+-- bypasses them. The roles are created without login here; the operator
+-- enables it with a password of their own:
+--   ALTER ROLE time_tracker_app LOGIN PASSWORD '...';
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'time_tracker_app') THEN
+        CREATE ROLE time_tracker_app NOLOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE NOBYPASSRLS;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'time_tracker_system') THEN
+        CREATE ROLE time_tracker_system NOLOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE BYPASSRLS;
+    END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO time_tracker_app, time_tracker_system;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO time_tracker_app, time_tracker_system;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO time_tracker_app, time_tracker_system;
+REVOKE ALL ON schema_migrations FROM time_tracker_app, time_tracker_system;
+
+-- Tables of later migrations are granted too.
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO time_tracker_app, time_tracker_system;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT USAGE, SELECT ON SEQUENCES TO time_tracker_app, time_tracker_system;
+
+CREATE OR REPLACE FUNCTION current_organization_uuid()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.organization_uuid', true), '')::uuid;
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE organizations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON organizations
+    USING (uuid = current_organization_uuid());
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users
+    USING (organization_uuid = current_organization_uuid());
+
+ALTER TABLE holiday_calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE holiday_calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON holiday_calendars
+    USING (organization_uuid = current_organization_uuid());
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+    USING (organization_uuid = current_organization_uuid());
+
+-- Tables owned by a user or a calendar follow the visibility of their owner.
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tasks FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tasks
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = tasks.user_uuid));
+
+ALTER TABLE task_histories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_histories FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON task_histories
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = task_histories.user_uuid));
+
+ALTER TABLE task_budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_budgets FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON task_budgets
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = task_budgets.user_uuid));
+
+ALTER TABLE pomodoro_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pomodoro_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON pomodoro_settings
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = pomodoro_settings.user_uuid));
+
+ALTER TABLE work_schedules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE work_schedules FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON work_schedules
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = work_schedules.user_uuid));
+
+ALTER TABLE absences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE absences FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON absences
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = absences.user_uuid));
+
+ALTER TABLE absence_balances ENABLE ROW LEVEL SECURITY;
+ALTER TABLE absence_balances FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON absence_balances
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = absence_balances.user_uuid));
+
+ALTER TABLE user_holiday_calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_holiday_calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_holiday_calendars
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = user_holiday_calendars.user_uuid));
+
+ALTER TABLE team_holiday_calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE team_holiday_calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON team_holiday_calendars
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = team_holiday_calendars.manager_uuid));
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON idempotency_keys
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = idempotency_keys.user_uuid));
+
+ALTER TABLE holidays ENABLE ROW LEVEL SECURITY;
+ALTER TABLE holidays FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON holidays
+    USING (EXISTS (SELECT 1 FROM holiday_calendars hc WHERE hc.uuid = holidays.calendar_uuid));
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN email VARCHAR(255);
+
+CREATE UNIQUE INDEX users_organization_email_key ON users (organization_uuid, lower(email));
+
+CREATE TABLE user_identities (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL,
+    UNIQUE (issuer, subject)
+);
+
+CREATE INDEX user_identities_user_uuid_idx ON user_identities (user_uuid);
+
+ALTER TABLE user_identities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_identities FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_identities
+    USING (EXISTS (SELECT 1 FROM users u WHERE u.uuid = user_identities.user_uuid));
@@ -0,0 +1,8 @@
+-- Encrypted passport numbers cannot be restored by SQL: run the reencrypt
+-- command with -decrypt before rolling back.
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_organization_uuid_passport_number_index_key,
+    DROP CONSTRAINT IF EXISTS users_passport_number_present,
+    DROP COLUMN IF EXISTS passport_number_index,
+    DROP COLUMN IF EXISTS passport_number_ciphertext,
+    ALTER COLUMN passport_number SET NOT NULL;
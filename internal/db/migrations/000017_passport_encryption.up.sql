@@ -0,0 +1,8 @@
+-- Passport numbers are encrypted by the application. Existing plain values are
+-- kept until the reencrypt command moves them to the encrypted columns.
+ALTER TABLE users
+    ALTER COLUMN passport_number DROP NOT NULL,
+    ADD COLUMN passport_number_ciphertext TEXT,
+    ADD COLUMN passport_number_index VARCHAR(64),
+    ADD CONSTRAINT users_passport_number_present CHECK (passport_number IS NOT NULL OR passport_number_ciphertext IS NOT NULL),
+    ADD CONSTRAINT users_organization_uuid_passport_number_index_key UNIQUE (organization_uuid, passport_number_index);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS pii_access_log;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS permissions;
+
+ALTER TABLE users DROP COLUMN IF EXISTS permissions;
@@ -0,0 +1,25 @@
+ALTER TABLE users ADD COLUMN permissions TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE api_keys ADD COLUMN permissions TEXT[] NOT NULL DEFAULT '{}';
+
+-- Full reads of personal data. Rows outlive the users involved, so they have
+-- no foreign keys to users.
+CREATE TABLE pii_access_log (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_uuid UUID NOT NULL REFERENCES organizations(uuid) ON DELETE CASCADE,
+    actor VARCHAR(255) NOT NULL,
+    actor_user_uuid UUID,
+    api_key_uuid UUID,
+    user_uuid UUID NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    reason TEXT,
+    accessed_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL
+);
+
+CREATE INDEX pii_access_log_user_uuid_idx ON pii_access_log (user_uuid, accessed_at);
+
+ALTER TABLE pii_access_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pii_access_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON pii_access_log
+    USING (organization_uuid = current_organization_uuid());
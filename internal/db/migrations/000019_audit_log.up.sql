@@ -0,0 +1,41 @@
+-- Append-only trail of the changes to users, tasks and history entries.
+-- Rows outlive the users involved, so they have no foreign keys to users.
+CREATE TABLE audit_log (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_uuid UUID NOT NULL REFERENCES organizations(uuid),
+    actor VARCHAR(255) NOT NULL,
+    actor_user_uuid UUID,
+    api_key_uuid UUID,
+    request_id VARCHAR(255),
+    source_ip VARCHAR(45),
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('user', 'task', 'task_history')),
+    entity_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC') NOT NULL
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (organization_uuid, created_at);
+CREATE INDEX audit_log_entity_uuid_idx ON audit_log (entity_uuid);
+CREATE INDEX audit_log_user_uuid_idx ON audit_log (user_uuid);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_log
+    USING (organization_uuid = current_organization_uuid());
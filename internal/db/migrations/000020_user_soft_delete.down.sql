@@ -0,0 +1,7 @@
+-- Audit entries cannot be removed, so the restored check leaves the existing
+-- restore and purge entries alone. Deactivated users become active again.
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete')) NOT VALID;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,6 @@
+-- Deleting a user deactivates it, keeping its history. Purging deletes it.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'));
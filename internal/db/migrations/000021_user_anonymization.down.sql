@@ -0,0 +1,27 @@
+-- Anonymized users have no passport number to restore. Their tracked time is
+-- kept for reports, so the rollback refuses to run rather than delete them.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE anonymized_at IS NOT NULL) THEN
+        RAISE EXCEPTION 'anonymized users exist, purge them before rolling back';
+    END IF;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Audit entries cannot be removed, so the restored check leaves the existing
+-- anonymize entries alone.
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')) NOT VALID;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_passport_number_present,
+    ADD CONSTRAINT users_passport_number_present
+        CHECK (passport_number IS NOT NULL OR passport_number_ciphertext IS NOT NULL),
+    DROP COLUMN IF EXISTS anonymized_at;
@@ -0,0 +1,24 @@
+-- Anonymized users keep their tracked time for reports, without personal data.
+ALTER TABLE users
+    ADD COLUMN anonymized_at TIMESTAMPTZ,
+    DROP CONSTRAINT users_passport_number_present,
+    ADD CONSTRAINT users_passport_number_present
+        CHECK (passport_number IS NOT NULL OR passport_number_ciphertext IS NOT NULL OR anonymized_at IS NOT NULL);
+
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge', 'anonymize'));
+
+-- The audit log stays append-only, except that anonymization may remove
+-- personal data from the recorded changes. It enables that for its
+-- transaction only, through the time_tracker.audit_redaction setting.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND current_setting('time_tracker.audit_redaction', true) = 'on'
+        AND to_jsonb(NEW) - 'before' - 'after' = to_jsonb(OLD) - 'before' - 'after' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
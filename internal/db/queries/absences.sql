@@ -0,0 +1,54 @@
+-- name: CreateAbsence :one
+INSERT INTO absences (user_uuid, type, start_date, end_date, half_day_start, half_day_end, note)
+VALUES (@user_uuid, @type, @start_date, @end_date, @half_day_start, @half_day_end, @note)
+RETURNING *;
+
+-- name: GetAbsencesInRange :many
+SELECT * FROM absences
+WHERE user_uuid = @user_uuid
+    AND start_date <= @to_date
+    AND end_date >= @from_date
+ORDER BY start_date;
+
+-- name: HasOverlappingAbsence :one
+SELECT EXISTS (
+    SELECT 1 FROM absences
+    WHERE user_uuid = @user_uuid
+        AND start_date <= @end_date
+        AND end_date >= @start_date
+);
+
+-- name: HasFullDayAbsenceOn :one
+SELECT EXISTS (
+    SELECT 1 FROM absences
+    WHERE user_uuid = @user_uuid
+        AND @day::date BETWEEN start_date AND end_date
+        AND NOT (@day::date = start_date AND half_day_start)
+        AND NOT (@day::date = end_date AND half_day_end)
+);
+
+-- name: DeleteAbsence :execrows
+DELETE FROM absences
+WHERE uuid = @absence_uuid AND user_uuid = @user_uuid;
+
+-- name: UpsertAbsenceBalance :one
+INSERT INTO absence_balances (user_uuid, year, type, allowance_days)
+VALUES (@user_uuid, @year, @type, @allowance_days)
+ON CONFLICT (user_uuid, year, type) DO UPDATE
+SET allowance_days = EXCLUDED.allowance_days
+RETURNING *;
+
+-- name: GetAbsenceBalances :many
+SELECT * FROM absence_balances
+WHERE user_uuid = @user_uuid AND year = @year
+ORDER BY type;
+
+-- name: GetAbsencesByUser :many
+SELECT * FROM absences
+WHERE user_uuid = @user_uuid
+ORDER BY start_date;
+
+-- name: GetAbsenceBalancesByUser :many
+SELECT * FROM absence_balances
+WHERE user_uuid = @user_uuid
+ORDER BY year, type;
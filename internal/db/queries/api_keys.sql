@@ -0,0 +1,37 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (organization_uuid, name, prefix, key_hash, created_by, user_uuid, role, permissions, expires_at)
+VALUES (@organization_uuid, @name, @prefix, @key_hash, @created_by, @user_uuid, @role, @permissions, @expires_at)
+RETURNING *;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys
+WHERE prefix = @prefix;
+
+-- name: GetAPIKeysByCreator :many
+SELECT * FROM api_keys
+WHERE organization_uuid = @organization_uuid AND created_by = @created_by
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE uuid = @api_key_uuid
+    AND organization_uuid = @organization_uuid
+    AND created_by = @created_by
+    AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE uuid = @api_key_uuid
+    AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: GetAPIKeysByUser :many
+SELECT * FROM api_keys
+WHERE user_uuid = @user_uuid
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKeysByUser :exec
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE user_uuid = @user_uuid AND revoked_at IS NULL;
@@ -0,0 +1,34 @@
+-- name: CreateAuditEntry :execrows
+INSERT INTO audit_log (organization_uuid, actor, actor_user_uuid, api_key_uuid, request_id, source_ip, entity, entity_uuid, user_uuid, action, before, after)
+SELECT u.organization_uuid, @actor::text, sqlc.narg('actor_user_uuid')::uuid, sqlc.narg('api_key_uuid')::uuid,
+    sqlc.narg('request_id')::text, sqlc.narg('source_ip')::text, @entity::text, @entity_uuid::uuid, u.uuid,
+    @action::text, sqlc.narg('before')::jsonb, sqlc.narg('after')::jsonb
+FROM users u
+WHERE u.uuid = @user_uuid;
+
+-- name: GetAuditEntries :many
+SELECT * FROM audit_log
+WHERE organization_uuid = @organization_uuid
+    AND (entity = sqlc.narg('entity') OR sqlc.narg('entity') IS NULL)
+    AND (entity_uuid = sqlc.narg('entity_uuid') OR sqlc.narg('entity_uuid') IS NULL)
+    AND (user_uuid = sqlc.narg('user_uuid') OR sqlc.narg('user_uuid') IS NULL)
+    AND (actor = sqlc.narg('actor') OR sqlc.narg('actor') IS NULL)
+    AND (action = sqlc.narg('action') OR sqlc.narg('action') IS NULL)
+    AND (created_at >= sqlc.narg('from_time') OR sqlc.narg('from_time') IS NULL)
+    AND (created_at < sqlc.narg('to_time') OR sqlc.narg('to_time') IS NULL)
+ORDER BY created_at DESC, uuid
+LIMIT @entry_limit OFFSET @entry_offset;
+
+-- name: GetAuditEntriesByUser :many
+SELECT * FROM audit_log
+WHERE user_uuid = @user_uuid OR actor_user_uuid = @user_uuid
+ORDER BY created_at, uuid;
+
+-- name: EnableAuditRedaction :exec
+SELECT set_config('time_tracker.audit_redaction', 'on', true);
+
+-- name: RedactUserAuditEntries :exec
+UPDATE audit_log
+SET before = before - @fields::text[],
+    after = after - @fields::text[]
+WHERE entity = 'user' AND entity_uuid = @user_uuid;
@@ -0,0 +1,73 @@
+-- name: UpsertHolidayCalendar :one
+INSERT INTO holiday_calendars (organization_uuid, name, region)
+VALUES (@organization_uuid, @name, @region)
+ON CONFLICT (organization_uuid, name) DO UPDATE
+SET region = EXCLUDED.region
+RETURNING *;
+
+-- name: GetHolidayCalendars :many
+SELECT * FROM holiday_calendars
+WHERE organization_uuid = @organization_uuid
+ORDER BY name;
+
+-- name: GetHolidayCalendarByUUID :one
+SELECT * FROM holiday_calendars
+WHERE uuid = @calendar_uuid AND organization_uuid = @organization_uuid;
+
+-- name: DeleteHolidayCalendar :execrows
+DELETE FROM holiday_calendars
+WHERE uuid = @calendar_uuid AND organization_uuid = @organization_uuid;
+
+-- name: DeleteHolidays :exec
+DELETE FROM holidays
+WHERE calendar_uuid = @calendar_uuid;
+
+-- name: CreateHolidays :execrows
+INSERT INTO holidays (calendar_uuid, day, name)
+SELECT @calendar_uuid::uuid, unnest(@days::date[]), unnest(@names::text[])
+ON CONFLICT (calendar_uuid, day) DO NOTHING;
+
+-- name: GetHolidaysInRange :many
+SELECT * FROM holidays
+WHERE calendar_uuid = @calendar_uuid
+    AND day BETWEEN @from_date AND @to_date
+ORDER BY day;
+
+-- name: SetUserHolidayCalendar :exec
+INSERT INTO user_holiday_calendars (user_uuid, calendar_uuid)
+VALUES (@user_uuid, @calendar_uuid)
+ON CONFLICT (user_uuid) DO UPDATE
+SET calendar_uuid = EXCLUDED.calendar_uuid;
+
+-- name: DeleteUserHolidayCalendar :execrows
+DELETE FROM user_holiday_calendars
+WHERE user_uuid = @user_uuid;
+
+-- name: SetTeamHolidayCalendar :exec
+INSERT INTO team_holiday_calendars (manager_uuid, calendar_uuid)
+VALUES (@manager_uuid, @calendar_uuid)
+ON CONFLICT (manager_uuid) DO UPDATE
+SET calendar_uuid = EXCLUDED.calendar_uuid;
+
+-- name: DeleteTeamHolidayCalendar :execrows
+DELETE FROM team_holiday_calendars
+WHERE manager_uuid = @manager_uuid;
+
+-- name: GetUserHolidaysInRange :many
+SELECT h.calendar_uuid, h.day, h.name
+FROM holidays h
+WHERE h.calendar_uuid = COALESCE(
+        (SELECT uhc.calendar_uuid FROM user_holiday_calendars uhc WHERE uhc.user_uuid = @user_uuid),
+        (
+            SELECT thc.calendar_uuid
+            FROM team_holiday_calendars thc
+            JOIN users u ON u.manager_uuid = thc.manager_uuid
+            WHERE u.uuid = @user_uuid
+        )
+    )
+    AND h.day BETWEEN @from_date AND @to_date
+ORDER BY h.day;
+
+-- name: GetUserHolidayCalendar :one
+SELECT calendar_uuid FROM user_holiday_calendars
+WHERE user_uuid = @user_uuid;
@@ -0,0 +1,29 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (user_uuid, key, request_hash, expires_at)
+VALUES (@user_uuid, @key, @request_hash, @expires_at)
+ON CONFLICT (user_uuid, key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    response_body = NULL,
+    created_at = CURRENT_TIMESTAMP,
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= NOW()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE user_uuid = @user_uuid AND key = @key;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys
+SET status_code = @status_code,
+    response_body = @response_body
+WHERE user_uuid = @user_uuid AND key = @key;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_uuid = @user_uuid AND key = @key;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= NOW();
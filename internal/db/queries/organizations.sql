@@ -0,0 +1,3 @@
+-- name: GetOrganizationByUUID :one
+SELECT * FROM organizations
+WHERE uuid = @organization_uuid;
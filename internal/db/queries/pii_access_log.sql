@@ -0,0 +1,15 @@
+-- name: CreatePIIAccesses :exec
+INSERT INTO pii_access_log (organization_uuid, actor, actor_user_uuid, api_key_uuid, user_uuid, field, operation, reason)
+SELECT @organization_uuid::uuid, @actor::text, sqlc.narg('actor_user_uuid')::uuid, sqlc.narg('api_key_uuid')::uuid,
+    unnest(@user_uuids::uuid[]), @field::text, @operation::text, sqlc.narg('reason')::text;
+
+-- name: GetPIIAccesses :many
+SELECT * FROM pii_access_log
+WHERE user_uuid = @user_uuid
+ORDER BY accessed_at DESC
+LIMIT @access_limit OFFSET @access_offset;
+
+-- name: GetPIIAccessesByUser :many
+SELECT * FROM pii_access_log
+WHERE user_uuid = @user_uuid OR actor_user_uuid = @user_uuid
+ORDER BY accessed_at;
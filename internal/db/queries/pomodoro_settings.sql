@@ -0,0 +1,26 @@
+-- name: GetOrCreatePomodoroSettings :one
+WITH inserted AS (
+    INSERT INTO pomodoro_settings (user_uuid)
+    VALUES (@user_uuid)
+    ON CONFLICT (user_uuid) DO NOTHING
+    RETURNING *
+)
+SELECT * FROM inserted
+UNION ALL
+SELECT * FROM pomodoro_settings
+WHERE user_uuid = @user_uuid
+LIMIT 1;
+
+-- name: UpdatePomodoroSettings :one
+UPDATE pomodoro_settings
+SET focus_minutes = coalesce(sqlc.narg('focus_minutes'), focus_minutes),
+    short_break_minutes = coalesce(sqlc.narg('short_break_minutes'), short_break_minutes),
+    long_break_minutes = coalesce(sqlc.narg('long_break_minutes'), long_break_minutes),
+    cycles_before_long_break = coalesce(sqlc.narg('cycles_before_long_break'), cycles_before_long_break),
+    auto_start_break = coalesce(sqlc.narg('auto_start_break'), auto_start_break)
+WHERE user_uuid = @user_uuid
+RETURNING *;
+
+-- name: GetPomodoroSettings :one
+SELECT * FROM pomodoro_settings
+WHERE user_uuid = @user_uuid;
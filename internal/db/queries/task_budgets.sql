@@ -0,0 +1,90 @@
+-- name: UpsertTaskBudget :one
+INSERT INTO task_budgets (user_uuid, name, estimate_minutes)
+VALUES (@user_uuid, @name, @estimate_minutes)
+ON CONFLICT (user_uuid, name) DO UPDATE
+SET estimate_minutes = EXCLUDED.estimate_minutes,
+    notified_threshold = CASE
+        WHEN task_budgets.estimate_minutes = EXCLUDED.estimate_minutes THEN task_budgets.notified_threshold
+        ELSE 0
+    END
+RETURNING *;
+
+-- name: GetTaskBudgetsUsage :many
+SELECT
+    tb.uuid,
+    tb.user_uuid,
+    tb.name,
+    tb.estimate_minutes,
+    tb.notified_threshold,
+    CAST(
+        COALESCE((
+            SELECT SUM(EXTRACT(EPOCH FROM (th.end_time - th.start_time)))
+            FROM task_histories th
+            WHERE th.user_uuid = tb.user_uuid AND th.name = tb.name
+        ), 0) +
+        COALESCE((
+            SELECT EXTRACT(EPOCH FROM (NOW() - t.start_time))
+            FROM tasks t
+            WHERE t.user_uuid = tb.user_uuid AND t.name = tb.name
+        ), 0)
+    AS BIGINT) AS actual_seconds
+FROM
+    task_budgets tb
+WHERE
+    tb.user_uuid = @user_uuid
+ORDER BY
+    tb.name;
+
+-- name: GetTaskBudgetUsageByName :one
+SELECT
+    tb.uuid,
+    tb.user_uuid,
+    tb.name,
+    tb.estimate_minutes,
+    tb.notified_threshold,
+    CAST(
+        COALESCE((
+            SELECT SUM(EXTRACT(EPOCH FROM (th.end_time - th.start_time)))
+            FROM task_histories th
+            WHERE th.user_uuid = tb.user_uuid AND th.name = tb.name
+        ), 0) +
+        COALESCE((
+            SELECT EXTRACT(EPOCH FROM (NOW() - t.start_time))
+            FROM tasks t
+            WHERE t.user_uuid = tb.user_uuid AND t.name = tb.name
+        ), 0)
+    AS BIGINT) AS actual_seconds
+FROM
+    task_budgets tb
+WHERE
+    tb.user_uuid = @user_uuid AND tb.name = @name;
+
+-- name: GetRunningTaskBudgetsUsage :many
+SELECT
+    tb.uuid,
+    tb.user_uuid,
+    tb.name,
+    tb.estimate_minutes,
+    tb.notified_threshold,
+    CAST(
+        COALESCE((
+            SELECT SUM(EXTRACT(EPOCH FROM (th.end_time - th.start_time)))
+            FROM task_histories th
+            WHERE th.user_uuid = tb.user_uuid AND th.name = tb.name
+        ), 0) +
+        EXTRACT(EPOCH FROM (NOW() - t.start_time))
+    AS BIGINT) AS actual_seconds
+FROM
+    task_budgets tb
+    JOIN tasks t ON t.user_uuid = tb.user_uuid AND t.name = tb.name
+WHERE
+    t.end_time IS NULL AND tb.notified_threshold < 100;
+
+-- name: UpdateTaskBudgetNotifiedThreshold :exec
+UPDATE task_budgets
+SET notified_threshold = @notified_threshold
+WHERE uuid = @budget_uuid;
+
+-- name: DeleteTaskBudget :execrows
+DELETE FROM task_budgets
+WHERE uuid = @budget_uuid AND user_uuid = @user_uuid;
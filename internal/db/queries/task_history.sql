@@ -1,7 +1,7 @@
 -- name: CreateTaskHistory :one
-INSERT INTO task_histories (user_uuid, name, start_time, end_time)
-VALUES (@user_uuid, @name, @start_time, @end_time)
-RETURNING name,
+INSERT INTO task_histories (user_uuid, name, description, notes, start_time, end_time, mode, interrupted)
+VALUES (@user_uuid, @name, @description, @notes, @start_time, @end_time, @mode, @interrupted)
+RETURNING uuid, user_uuid, name, description, notes, start_time, end_time, mode, version,
     CONCAT(
         FLOOR(EXTRACT(EPOCH FROM (end_time - start_time)) / 3600), ' hours ',
         FLOOR(EXTRACT(EPOCH FROM (end_time - start_time)) / 60 % 60), ' minutes'
@@ -11,6 +11,8 @@ RETURNING name,
 WITH task_durations AS (
     SELECT
         th.name AS task_name,
+        th.description,
+        th.notes,
         EXTRACT(EPOCH FROM (th.end_time - th.start_time)) AS duration_seconds
     FROM
         task_histories th
@@ -19,6 +21,8 @@ WITH task_durations AS (
 )
 SELECT
     td.task_name,
+    td.description,
+    td.notes,
     CONCAT(
         FLOOR(td.duration_seconds / 3600), ' hours ',
         FLOOR((td.duration_seconds / 60) % 60), ' minutes'
@@ -31,3 +35,126 @@ FROM
     task_durations td
 ORDER BY
     td.duration_seconds desc;
+
+-- name: GetCompletedPomodorosByPeriod :many
+SELECT
+    DATE(th.end_time AT TIME ZONE 'UTC') AS day,
+    COUNT(*) AS completed
+FROM
+    task_histories th
+WHERE
+    th.user_uuid = @user_uuid
+    AND th.mode = 'focus'
+    AND NOT th.interrupted
+    AND th.end_time >= NOW() - CAST(@period AS INTERVAL)
+GROUP BY
+    day
+ORDER BY
+    day;
+
+-- name: CountCompletedPomodorosToday :one
+SELECT COUNT(*) FROM task_histories
+WHERE user_uuid = @user_uuid
+    AND mode = 'focus'
+    AND NOT interrupted
+    AND end_time >= date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+
+-- name: GetDailyTrackedSeconds :many
+SELECT
+    DATE(th.start_time AT TIME ZONE 'UTC') AS day,
+    CAST(SUM(EXTRACT(EPOCH FROM (th.end_time - th.start_time))) AS BIGINT) AS tracked_seconds
+FROM
+    task_histories th
+WHERE
+    th.user_uuid = @user_uuid
+    AND th.start_time >= @from_time
+    AND th.start_time < @to_time
+GROUP BY
+    day
+ORDER BY
+    day;
+
+-- name: SearchTaskHistory :many
+SELECT
+    th.uuid,
+    th.user_uuid,
+    th.name,
+    th.description,
+    th.notes,
+    th.start_time,
+    th.end_time,
+    ts_rank(th.search_vector, q.query) AS rank,
+    ts_headline('russian', th.name, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+    ts_headline('russian', concat_ws(' ', th.description, th.notes), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS text_highlight
+FROM
+    task_histories th,
+    websearch_to_tsquery('russian', @query) AS q(query)
+WHERE
+    (th.user_uuid = @user_uuid OR (@team::bool AND th.user_uuid IN (SELECT uuid FROM users WHERE manager_uuid = @user_uuid)))
+    AND th.search_vector @@ q.query
+    AND (th.start_time >= sqlc.narg('from_time') OR sqlc.narg('from_time') IS NULL)
+    AND (th.start_time < sqlc.narg('to_time') OR sqlc.narg('to_time') IS NULL)
+ORDER BY
+    rank DESC,
+    th.start_time DESC
+LIMIT @result_limit OFFSET @result_offset;
+
+-- name: SuggestTaskNames :many
+SELECT
+    th.name,
+    COUNT(*) AS uses,
+    MAX(th.end_time)::timestamptz AS last_used,
+    (
+        word_similarity(@query, th.name)
+        + LN(1 + COUNT(*)) / 2
+        + 1 / (1 + EXTRACT(EPOCH FROM (NOW() - MAX(th.end_time))) / 604800)
+    )::float8 AS score
+FROM
+    task_histories th
+WHERE
+    (th.user_uuid = @user_uuid OR (@team::bool AND th.user_uuid IN (SELECT uuid FROM users WHERE manager_uuid = @user_uuid)))
+    AND (th.name ILIKE @pattern OR @query <% th.name)
+GROUP BY
+    th.name
+ORDER BY
+    bool_or(th.name ILIKE @pattern) DESC,
+    score DESC,
+    th.name
+LIMIT @result_limit;
+
+-- name: GetLastTaskHistoryEndTime :one
+SELECT MAX(end_time)::timestamptz AS end_time FROM task_histories
+WHERE user_uuid = @user_uuid;
+
+-- name: GetLastTaskHistory :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE user_uuid = @user_uuid
+ORDER BY end_time DESC
+LIMIT 1;
+
+-- name: GetTaskHistoryByUUID :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE uuid = @entry_uuid AND user_uuid = @user_uuid;
+
+-- name: GetTaskHistoryByUUIDForUpdate :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE uuid = @entry_uuid AND user_uuid = @user_uuid
+FOR UPDATE;
+
+-- name: UpdateTaskHistory :one
+UPDATE task_histories
+SET name = coalesce(sqlc.narg('name'), name),
+    description = coalesce(sqlc.narg('description'), description),
+    notes = coalesce(sqlc.narg('notes'), notes)
+WHERE uuid = @entry_uuid AND user_uuid = @user_uuid
+    AND (version = ANY(sqlc.narg('versions')::int[]) OR sqlc.narg('versions') IS NULL)
+RETURNING uuid, user_uuid, name, description, notes, start_time, end_time, mode, version;
+
+-- name: DeleteTaskHistory :execrows
+DELETE FROM task_histories
+WHERE uuid = @entry_uuid AND user_uuid = @user_uuid;
+
+-- name: GetTaskHistoriesByUser :many
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE user_uuid = @user_uuid
+ORDER BY start_time;
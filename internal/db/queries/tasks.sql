@@ -1,14 +1,30 @@
 -- name: CreateTask :one
-INSERT INTO tasks (user_uuid, name)
-VALUES (@user_uuid, @name)
+INSERT INTO tasks (user_uuid, name, description, notes, mode, start_time, planned_end_time)
+VALUES (
+    @user_uuid, @name, @description, @notes, @mode,
+    COALESCE(sqlc.narg('start_time'), NOW()),
+    COALESCE(sqlc.narg('start_time'), NOW()) + make_interval(mins => sqlc.narg('planned_minutes'))
+)
 RETURNING *;
 
+-- name: GetActiveTask :one
+SELECT * FROM tasks
+WHERE user_uuid = @user_uuid;
+
 -- name: UpdateTaskEndTime :one
 UPDATE tasks
-SET end_time = NOW()
+SET end_time = LEAST(
+    COALESCE(sqlc.narg('end_time'), NOW()),
+    COALESCE(planned_end_time, sqlc.narg('end_time'), NOW())
+)
 WHERE user_uuid = @user_uuid
 RETURNING *;
 
 -- name: DeleteTask :exec
 DELETE FROM tasks
 WHERE user_uuid = @user_uuid;
+
+-- name: DeleteExpiredTasks :many
+DELETE FROM tasks
+WHERE planned_end_time <= NOW()
+RETURNING *;
@@ -0,0 +1,17 @@
+-- name: GetUserByIdentity :one
+SELECT * FROM users
+WHERE uuid = (SELECT user_uuid FROM user_identities WHERE issuer = @issuer AND subject = @subject);
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_uuid)
+VALUES (@issuer, @subject, @user_uuid)
+ON CONFLICT (issuer, subject) DO NOTHING;
+
+-- name: GetUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_uuid = @user_uuid
+ORDER BY created_at;
+
+-- name: DeleteUserIdentities :exec
+DELETE FROM user_identities
+WHERE user_uuid = @user_uuid;
@@ -1,16 +1,18 @@
 -- name: CreateUser :one
-INSERT INTO users (passport_number, surname, name, patronymic, address)
-VALUES (@passport_number, @surname, @name, @patronymic, @address)
+INSERT INTO users (organization_uuid, passport_number_ciphertext, passport_number_index, surname, name, patronymic, address, email)
+VALUES (@organization_uuid, @passport_number_ciphertext, @passport_number_index, @surname, @name, @patronymic, @address, @email)
 RETURNING *;
 
 -- name: GetUsers :many
 SELECT * FROM users
-WHERE
-    (passport_number = sqlc.narg('passport_number') OR sqlc.narg('passport_number') IS NULL)
+WHERE organization_uuid = @organization_uuid
+    AND (passport_number_index = ANY(@passport_number_indexes::text[]) OR passport_number = sqlc.narg('passport_number') OR sqlc.narg('passport_number')::text IS NULL)
     AND (surname = sqlc.narg('surname') OR sqlc.narg('surname') IS NULL)
     AND (name = sqlc.narg('name') OR sqlc.narg('name') IS NULL)
     AND (patronymic = sqlc.narg('patronymic') OR sqlc.narg('patronymic') IS NULL)
     AND (address = sqlc.narg('address') OR sqlc.narg('address') IS NULL)
+    AND (manager_uuid = sqlc.narg('team_of') OR uuid = sqlc.narg('team_of') OR sqlc.narg('team_of')::uuid IS NULL)
+    AND (deleted_at IS NULL OR @include_deleted::bool)
 LIMIT @user_limit OFFSET @user_offset;
 
 -- name: GetUserByUUID :one
@@ -19,18 +21,81 @@ WHERE uuid = @user_uuid;
 
 -- name: GetUserByPassportNumber :one
 SELECT * FROM users
-WHERE passport_number = @passport_number;
+WHERE organization_uuid = @organization_uuid
+    AND (passport_number_index = ANY(@passport_number_indexes::text[]) OR passport_number = @passport_number)
+LIMIT 1;
+
+-- name: GetUsersByEmail :many
+SELECT * FROM users
+WHERE lower(email) = lower(@email::text)
+    AND deleted_at IS NULL;
+
+-- name: GetUserByUUIDForUpdate :one
+SELECT * FROM users
+WHERE uuid = @user_uuid
+FOR UPDATE;
 
 -- name: UpdateUserByUUID :one
 UPDATE users
-SET surname = coalesce(sqlc.narg('surname'), surname),
-    name = coalesce(sqlc.narg('name'), name),
-    patronymic = coalesce(sqlc.narg('patronymic'), patronymic),
-    address = coalesce(sqlc.narg('address'), address),
-    passport_number = coalesce(sqlc.narg('passport_number'), passport_number)
+SET surname = @surname,
+    name = @name,
+    patronymic = @patronymic,
+    address = @address,
+    passport_number = NULL,
+    passport_number_ciphertext = @passport_number_ciphertext,
+    passport_number_index = @passport_number_index,
+    email = @email
+WHERE uuid = @user_uuid
+RETURNING *;
+
+-- name: DeactivateUserByUUID :one
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE uuid = @user_uuid
+RETURNING *;
+
+-- name: RestoreUserByUUID :one
+UPDATE users
+SET deleted_at = NULL
 WHERE uuid = @user_uuid
 RETURNING *;
 
--- name: DeleteUserByUUID :exec
+-- name: PurgeUserByUUID :exec
 DELETE FROM users
 WHERE uuid = @user_uuid;
+
+-- name: SetUserRole :one
+UPDATE users
+SET role = @role,
+    manager_uuid = @manager_uuid,
+    permissions = @permissions
+WHERE uuid = @user_uuid
+RETURNING *;
+
+-- name: GetUsersPage :many
+SELECT * FROM users
+WHERE uuid > @after_uuid
+ORDER BY uuid
+LIMIT @page_size;
+
+-- name: SetUserPassportNumber :exec
+UPDATE users
+SET passport_number = @passport_number,
+    passport_number_ciphertext = @passport_number_ciphertext,
+    passport_number_index = @passport_number_index
+WHERE uuid = @user_uuid;
+
+-- name: AnonymizeUserByUUID :one
+UPDATE users
+SET surname = @placeholder,
+    name = @placeholder,
+    patronymic = NULL,
+    address = @placeholder,
+    passport_number = NULL,
+    passport_number_ciphertext = NULL,
+    passport_number_index = NULL,
+    email = NULL,
+    anonymized_at = CURRENT_TIMESTAMP,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE uuid = @user_uuid
+RETURNING *;
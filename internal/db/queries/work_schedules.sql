@@ -0,0 +1,29 @@
+-- name: UpsertWorkSchedule :one
+INSERT INTO work_schedules (
+    user_uuid, effective_from,
+    monday_minutes, tuesday_minutes, wednesday_minutes, thursday_minutes,
+    friday_minutes, saturday_minutes, sunday_minutes
+)
+VALUES (
+    @user_uuid, @effective_from,
+    @monday_minutes, @tuesday_minutes, @wednesday_minutes, @thursday_minutes,
+    @friday_minutes, @saturday_minutes, @sunday_minutes
+)
+ON CONFLICT (user_uuid, effective_from) DO UPDATE
+SET monday_minutes = EXCLUDED.monday_minutes,
+    tuesday_minutes = EXCLUDED.tuesday_minutes,
+    wednesday_minutes = EXCLUDED.wednesday_minutes,
+    thursday_minutes = EXCLUDED.thursday_minutes,
+    friday_minutes = EXCLUDED.friday_minutes,
+    saturday_minutes = EXCLUDED.saturday_minutes,
+    sunday_minutes = EXCLUDED.sunday_minutes
+RETURNING *;
+
+-- name: GetWorkSchedules :many
+SELECT * FROM work_schedules
+WHERE user_uuid = @user_uuid
+ORDER BY effective_from;
+
+-- name: DeleteWorkSchedule :execrows
+DELETE FROM work_schedules
+WHERE uuid = @schedule_uuid AND user_uuid = @user_uuid;
@@ -8,29 +8,198 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Task struct {
+type Absence struct {
+	Uuid         pgtype.UUID        `json:"uuid"`
+	UserUuid     pgtype.UUID        `json:"user_uuid"`
+	Type         string             `json:"type"`
+	StartDate    pgtype.Date        `json:"start_date"`
+	EndDate      pgtype.Date        `json:"end_date"`
+	HalfDayStart bool               `json:"half_day_start"`
+	HalfDayEnd   bool               `json:"half_day_end"`
+	Note         pgtype.Text        `json:"note"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AbsenceBalance struct {
+	UserUuid      pgtype.UUID `json:"user_uuid"`
+	Year          int32       `json:"year"`
+	Type          string      `json:"type"`
+	AllowanceDays float64     `json:"allowance_days"`
+}
+
+type ApiKey struct {
+	Uuid             pgtype.UUID        `json:"uuid"`
+	Name             string             `json:"name"`
+	Prefix           string             `json:"prefix"`
+	KeyHash          string             `json:"key_hash"`
+	CreatedBy        string             `json:"created_by"`
+	UserUuid         pgtype.UUID        `json:"user_uuid"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt       pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt        pgtype.Timestamptz `json:"revoked_at"`
+	Role             string             `json:"role"`
+	OrganizationUuid pgtype.UUID        `json:"organization_uuid"`
+	Permissions      []string           `json:"permissions"`
+}
+
+type AuditLog struct {
+	Uuid             pgtype.UUID        `json:"uuid"`
+	OrganizationUuid pgtype.UUID        `json:"organization_uuid"`
+	Actor            string             `json:"actor"`
+	ActorUserUuid    pgtype.UUID        `json:"actor_user_uuid"`
+	ApiKeyUuid       pgtype.UUID        `json:"api_key_uuid"`
+	RequestID        pgtype.Text        `json:"request_id"`
+	SourceIp         pgtype.Text        `json:"source_ip"`
+	Entity           string             `json:"entity"`
+	EntityUuid       pgtype.UUID        `json:"entity_uuid"`
+	UserUuid         pgtype.UUID        `json:"user_uuid"`
+	Action           string             `json:"action"`
+	Before           []byte             `json:"before"`
+	After            []byte             `json:"after"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type Holiday struct {
+	CalendarUuid pgtype.UUID `json:"calendar_uuid"`
+	Day          pgtype.Date `json:"day"`
+	Name         string      `json:"name"`
+}
+
+type HolidayCalendar struct {
+	Uuid             pgtype.UUID        `json:"uuid"`
+	Name             string             `json:"name"`
+	Region           pgtype.Text        `json:"region"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	OrganizationUuid pgtype.UUID        `json:"organization_uuid"`
+}
+
+type IdempotencyKey struct {
+	UserUuid     pgtype.UUID        `json:"user_uuid"`
+	Key          string             `json:"key"`
+	RequestHash  string             `json:"request_hash"`
+	StatusCode   pgtype.Int4        `json:"status_code"`
+	ResponseBody []byte             `json:"response_body"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Organization struct {
 	Uuid      pgtype.UUID        `json:"uuid"`
-	UserUuid  pgtype.UUID        `json:"user_uuid"`
 	Name      string             `json:"name"`
-	StartTime pgtype.Timestamptz `json:"start_time"`
-	EndTime   pgtype.Timestamptz `json:"end_time"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type PiiAccessLog struct {
+	Uuid             pgtype.UUID        `json:"uuid"`
+	OrganizationUuid pgtype.UUID        `json:"organization_uuid"`
+	Actor            string             `json:"actor"`
+	ActorUserUuid    pgtype.UUID        `json:"actor_user_uuid"`
+	ApiKeyUuid       pgtype.UUID        `json:"api_key_uuid"`
+	UserUuid         pgtype.UUID        `json:"user_uuid"`
+	Field            string             `json:"field"`
+	Operation        string             `json:"operation"`
+	Reason           pgtype.Text        `json:"reason"`
+	AccessedAt       pgtype.Timestamptz `json:"accessed_at"`
+}
+
+type PomodoroSetting struct {
+	UserUuid              pgtype.UUID        `json:"user_uuid"`
+	FocusMinutes          int32              `json:"focus_minutes"`
+	ShortBreakMinutes     int32              `json:"short_break_minutes"`
+	LongBreakMinutes      int32              `json:"long_break_minutes"`
+	CyclesBeforeLongBreak int32              `json:"cycles_before_long_break"`
+	AutoStartBreak        bool               `json:"auto_start_break"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Task struct {
+	Uuid           pgtype.UUID        `json:"uuid"`
+	UserUuid       pgtype.UUID        `json:"user_uuid"`
+	Name           string             `json:"name"`
+	StartTime      pgtype.Timestamptz `json:"start_time"`
+	EndTime        pgtype.Timestamptz `json:"end_time"`
+	Mode           string             `json:"mode"`
+	PlannedEndTime pgtype.Timestamptz `json:"planned_end_time"`
+	Description    pgtype.Text        `json:"description"`
+	Notes          pgtype.Text        `json:"notes"`
+}
+
+type TaskBudget struct {
+	Uuid              pgtype.UUID        `json:"uuid"`
+	UserUuid          pgtype.UUID        `json:"user_uuid"`
+	Name              string             `json:"name"`
+	EstimateMinutes   int32              `json:"estimate_minutes"`
+	NotifiedThreshold int32              `json:"notified_threshold"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
 type TaskHistory struct {
+	Uuid         pgtype.UUID        `json:"uuid"`
+	UserUuid     pgtype.UUID        `json:"user_uuid"`
+	Name         string             `json:"name"`
+	StartTime    pgtype.Timestamptz `json:"start_time"`
+	EndTime      pgtype.Timestamptz `json:"end_time"`
+	Mode         string             `json:"mode"`
+	Interrupted  bool               `json:"interrupted"`
+	Description  pgtype.Text        `json:"description"`
+	Notes        pgtype.Text        `json:"notes"`
+	SearchVector interface{}        `json:"search_vector"`
+	Version      int32              `json:"version"`
+}
+
+type TeamHolidayCalendar struct {
+	ManagerUuid  pgtype.UUID `json:"manager_uuid"`
+	CalendarUuid pgtype.UUID `json:"calendar_uuid"`
+}
+
+type User struct {
+	Uuid                     pgtype.UUID        `json:"uuid"`
+	PassportNumber           pgtype.Text        `json:"passport_number"`
+	Surname                  string             `json:"surname"`
+	Name                     string             `json:"name"`
+	Patronymic               pgtype.Text        `json:"patronymic"`
+	Address                  string             `json:"address"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
+	Version                  int32              `json:"version"`
+	Role                     string             `json:"role"`
+	ManagerUuid              pgtype.UUID        `json:"manager_uuid"`
+	OrganizationUuid         pgtype.UUID        `json:"organization_uuid"`
+	Email                    pgtype.Text        `json:"email"`
+	PassportNumberCiphertext pgtype.Text        `json:"passport_number_ciphertext"`
+	PassportNumberIndex      pgtype.Text        `json:"passport_number_index"`
+	Permissions              []string           `json:"permissions"`
+	DeletedAt                pgtype.Timestamptz `json:"deleted_at"`
+	AnonymizedAt             pgtype.Timestamptz `json:"anonymized_at"`
+}
+
+type UserHolidayCalendar struct {
+	UserUuid     pgtype.UUID `json:"user_uuid"`
+	CalendarUuid pgtype.UUID `json:"calendar_uuid"`
+}
+
+type UserIdentity struct {
 	Uuid      pgtype.UUID        `json:"uuid"`
+	Issuer    string             `json:"issuer"`
+	Subject   string             `json:"subject"`
 	UserUuid  pgtype.UUID        `json:"user_uuid"`
-	Name      string             `json:"name"`
-	StartTime pgtype.Timestamptz `json:"start_time"`
-	EndTime   pgtype.Timestamptz `json:"end_time"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
-type User struct {
-	Uuid           pgtype.UUID        `json:"uuid"`
-	PassportNumber string             `json:"passport_number"`
-	Surname        string             `json:"surname"`
-	Name           string             `json:"name"`
-	Patronymic     pgtype.Text        `json:"patronymic"`
-	Address        string             `json:"address"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+type WorkSchedule struct {
+	Uuid             pgtype.UUID        `json:"uuid"`
+	UserUuid         pgtype.UUID        `json:"user_uuid"`
+	EffectiveFrom    pgtype.Date        `json:"effective_from"`
+	MondayMinutes    int32              `json:"monday_minutes"`
+	TuesdayMinutes   int32              `json:"tuesday_minutes"`
+	WednesdayMinutes int32              `json:"wednesday_minutes"`
+	ThursdayMinutes  int32              `json:"thursday_minutes"`
+	FridayMinutes    int32              `json:"friday_minutes"`
+	SaturdayMinutes  int32              `json:"saturday_minutes"`
+	SundayMinutes    int32              `json:"sunday_minutes"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 }
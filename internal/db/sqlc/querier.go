@@ -11,17 +11,98 @@ import (
 )
 
 type Querier interface {
+	AnonymizeUserByUUID(ctx context.Context, arg AnonymizeUserByUUIDParams) (User, error)
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	CountCompletedPomodorosToday(ctx context.Context, userUuid pgtype.UUID) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAbsence(ctx context.Context, arg CreateAbsenceParams) (Absence, error)
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (int64, error)
+	CreateHolidays(ctx context.Context, arg CreateHolidaysParams) (int64, error)
+	CreatePIIAccesses(ctx context.Context, arg CreatePIIAccessesParams) error
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
 	CreateTaskHistory(ctx context.Context, arg CreateTaskHistoryParams) (CreateTaskHistoryRow, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error
+	DeactivateUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User, error)
+	DeleteAbsence(ctx context.Context, arg DeleteAbsenceParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteExpiredTasks(ctx context.Context) ([]Task, error)
+	DeleteHolidayCalendar(ctx context.Context, arg DeleteHolidayCalendarParams) (int64, error)
+	DeleteHolidays(ctx context.Context, calendarUuid pgtype.UUID) error
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
 	DeleteTask(ctx context.Context, userUuid pgtype.UUID) error
-	DeleteUserByUUID(ctx context.Context, userUuid pgtype.UUID) error
+	DeleteTaskBudget(ctx context.Context, arg DeleteTaskBudgetParams) (int64, error)
+	DeleteTaskHistory(ctx context.Context, arg DeleteTaskHistoryParams) (int64, error)
+	DeleteTeamHolidayCalendar(ctx context.Context, managerUuid pgtype.UUID) (int64, error)
+	DeleteUserHolidayCalendar(ctx context.Context, userUuid pgtype.UUID) (int64, error)
+	DeleteUserIdentities(ctx context.Context, userUuid pgtype.UUID) error
+	DeleteWorkSchedule(ctx context.Context, arg DeleteWorkScheduleParams) (int64, error)
+	EnableAuditRedaction(ctx context.Context) error
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetAPIKeysByCreator(ctx context.Context, arg GetAPIKeysByCreatorParams) ([]ApiKey, error)
+	GetAPIKeysByUser(ctx context.Context, userUuid pgtype.UUID) ([]ApiKey, error)
+	GetAbsenceBalances(ctx context.Context, arg GetAbsenceBalancesParams) ([]AbsenceBalance, error)
+	GetAbsenceBalancesByUser(ctx context.Context, userUuid pgtype.UUID) ([]AbsenceBalance, error)
+	GetAbsencesByUser(ctx context.Context, userUuid pgtype.UUID) ([]Absence, error)
+	GetAbsencesInRange(ctx context.Context, arg GetAbsencesInRangeParams) ([]Absence, error)
+	GetActiveTask(ctx context.Context, userUuid pgtype.UUID) (Task, error)
+	GetAuditEntries(ctx context.Context, arg GetAuditEntriesParams) ([]AuditLog, error)
+	GetAuditEntriesByUser(ctx context.Context, userUuid pgtype.UUID) ([]AuditLog, error)
+	GetCompletedPomodorosByPeriod(ctx context.Context, arg GetCompletedPomodorosByPeriodParams) ([]GetCompletedPomodorosByPeriodRow, error)
+	GetDailyTrackedSeconds(ctx context.Context, arg GetDailyTrackedSecondsParams) ([]GetDailyTrackedSecondsRow, error)
+	GetHolidayCalendarByUUID(ctx context.Context, arg GetHolidayCalendarByUUIDParams) (HolidayCalendar, error)
+	GetHolidayCalendars(ctx context.Context, organizationUuid pgtype.UUID) ([]HolidayCalendar, error)
+	GetHolidaysInRange(ctx context.Context, arg GetHolidaysInRangeParams) ([]Holiday, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetLastTaskHistory(ctx context.Context, userUuid pgtype.UUID) (GetLastTaskHistoryRow, error)
+	GetLastTaskHistoryEndTime(ctx context.Context, userUuid pgtype.UUID) (pgtype.Timestamptz, error)
+	GetOrCreatePomodoroSettings(ctx context.Context, userUuid pgtype.UUID) (PomodoroSetting, error)
+	GetOrganizationByUUID(ctx context.Context, organizationUuid pgtype.UUID) (Organization, error)
+	GetPIIAccesses(ctx context.Context, arg GetPIIAccessesParams) ([]PiiAccessLog, error)
+	GetPIIAccessesByUser(ctx context.Context, userUuid pgtype.UUID) ([]PiiAccessLog, error)
+	GetPomodoroSettings(ctx context.Context, userUuid pgtype.UUID) (PomodoroSetting, error)
+	GetRunningTaskBudgetsUsage(ctx context.Context) ([]GetRunningTaskBudgetsUsageRow, error)
+	GetTaskBudgetUsageByName(ctx context.Context, arg GetTaskBudgetUsageByNameParams) (GetTaskBudgetUsageByNameRow, error)
+	GetTaskBudgetsUsage(ctx context.Context, userUuid pgtype.UUID) ([]GetTaskBudgetsUsageRow, error)
+	GetTaskHistoriesByUser(ctx context.Context, userUuid pgtype.UUID) ([]GetTaskHistoriesByUserRow, error)
+	GetTaskHistoryByUUID(ctx context.Context, arg GetTaskHistoryByUUIDParams) (GetTaskHistoryByUUIDRow, error)
+	GetTaskHistoryByUUIDForUpdate(ctx context.Context, arg GetTaskHistoryByUUIDForUpdateParams) (GetTaskHistoryByUUIDForUpdateRow, error)
 	GetTasksResultByPeriod(ctx context.Context, arg GetTasksResultByPeriodParams) ([]GetTasksResultByPeriodRow, error)
-	GetUserByPassportNumber(ctx context.Context, passportNumber string) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
+	GetUserByPassportNumber(ctx context.Context, arg GetUserByPassportNumberParams) (User, error)
 	GetUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User, error)
+	GetUserByUUIDForUpdate(ctx context.Context, userUuid pgtype.UUID) (User, error)
+	GetUserHolidayCalendar(ctx context.Context, userUuid pgtype.UUID) (pgtype.UUID, error)
+	GetUserHolidaysInRange(ctx context.Context, arg GetUserHolidaysInRangeParams) ([]Holiday, error)
+	GetUserIdentities(ctx context.Context, userUuid pgtype.UUID) ([]UserIdentity, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
-	UpdateTaskEndTime(ctx context.Context, userUuid pgtype.UUID) (Task, error)
+	GetUsersByEmail(ctx context.Context, email string) ([]User, error)
+	GetUsersPage(ctx context.Context, arg GetUsersPageParams) ([]User, error)
+	GetWorkSchedules(ctx context.Context, userUuid pgtype.UUID) ([]WorkSchedule, error)
+	HasFullDayAbsenceOn(ctx context.Context, arg HasFullDayAbsenceOnParams) (bool, error)
+	HasOverlappingAbsence(ctx context.Context, arg HasOverlappingAbsenceParams) (bool, error)
+	PurgeUserByUUID(ctx context.Context, userUuid pgtype.UUID) error
+	RedactUserAuditEntries(ctx context.Context, arg RedactUserAuditEntriesParams) error
+	RestoreUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeAPIKeysByUser(ctx context.Context, userUuid pgtype.UUID) error
+	SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error
+	SearchTaskHistory(ctx context.Context, arg SearchTaskHistoryParams) ([]SearchTaskHistoryRow, error)
+	SetTeamHolidayCalendar(ctx context.Context, arg SetTeamHolidayCalendarParams) error
+	SetUserHolidayCalendar(ctx context.Context, arg SetUserHolidayCalendarParams) error
+	SetUserPassportNumber(ctx context.Context, arg SetUserPassportNumberParams) error
+	SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error)
+	SuggestTaskNames(ctx context.Context, arg SuggestTaskNamesParams) ([]SuggestTaskNamesRow, error)
+	TouchAPIKey(ctx context.Context, apiKeyUuid pgtype.UUID) error
+	UpdatePomodoroSettings(ctx context.Context, arg UpdatePomodoroSettingsParams) (PomodoroSetting, error)
+	UpdateTaskBudgetNotifiedThreshold(ctx context.Context, arg UpdateTaskBudgetNotifiedThresholdParams) error
+	UpdateTaskEndTime(ctx context.Context, arg UpdateTaskEndTimeParams) (Task, error)
+	UpdateTaskHistory(ctx context.Context, arg UpdateTaskHistoryParams) (UpdateTaskHistoryRow, error)
 	UpdateUserByUUID(ctx context.Context, arg UpdateUserByUUIDParams) (User, error)
+	UpsertAbsenceBalance(ctx context.Context, arg UpsertAbsenceBalanceParams) (AbsenceBalance, error)
+	UpsertHolidayCalendar(ctx context.Context, arg UpsertHolidayCalendarParams) (HolidayCalendar, error)
+	UpsertTaskBudget(ctx context.Context, arg UpsertTaskBudgetParams) (TaskBudget, error)
+	UpsertWorkSchedule(ctx context.Context, arg UpsertWorkScheduleParams) (WorkSchedule, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store provides all queries plus the ability to run several of them in a
+// single transaction.
+type Store interface {
+	Querier
+	// ExecTx runs fn in a transaction with the default isolation level.
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+	// ExecTxWithIsolation runs fn in a transaction with the given isolation level.
+	ExecTxWithIsolation(ctx context.Context, isoLevel pgx.TxIsoLevel, fn func(Querier) error) error
+}
+
+type StoreConfig struct {
+	// IsoLevel is the isolation level used by ExecTx.
+	IsoLevel pgx.TxIsoLevel
+	// MaxRetries is how many times a transaction is run again after a
+	// serialization failure or a deadlock.
+	MaxRetries int
+	// RetryDelay is the base of the exponential backoff between retries.
+	RetryDelay time.Duration
+}
+
+type SQLStore struct {
+	*Queries
+	pool   *pgxpool.Pool
+	config StoreConfig
+}
+
+func NewStore(pool *pgxpool.Pool, config StoreConfig) *SQLStore {
+	if config.IsoLevel == "" {
+		config.IsoLevel = pgx.ReadCommitted
+	}
+
+	return &SQLStore{
+		Queries: New(pool),
+		pool:    pool,
+		config:  config,
+	}
+}
+
+func (s *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	return s.ExecTxWithIsolation(ctx, s.config.IsoLevel, fn)
+}
+
+// ExecTxWithIsolation commits when fn returns nil and rolls back otherwise.
+// Transactions failing with a serialization failure or a deadlock are run
+// again, so fn must not have side effects outside the database.
+func (s *SQLStore) ExecTxWithIsolation(ctx context.Context, isoLevel pgx.TxIsoLevel, fn func(Querier) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = s.execTx(ctx, isoLevel, fn)
+		if err == nil || !isRetryable(err) || attempt >= s.config.MaxRetries {
+			return err
+		}
+
+		// Full jitter keeps concurrent retries from colliding again.
+		backoff := s.config.RetryDelay << attempt
+		if backoff > 0 {
+			backoff = time.Duration(rand.Int63n(int64(backoff)))
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (s *SQLStore) execTx(ctx context.Context, isoLevel pgx.TxIsoLevel, fn func(Querier) error) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(s.Queries.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// isRetryable reports whether the transaction failed only because it ran
+// concurrently with another one.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
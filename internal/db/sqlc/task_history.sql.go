@@ -11,10 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countCompletedPomodorosToday = `-- name: CountCompletedPomodorosToday :one
+SELECT COUNT(*) FROM task_histories
+WHERE user_uuid = $1
+    AND mode = 'focus'
+    AND NOT interrupted
+    AND end_time >= date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+`
+
+func (q *Queries) CountCompletedPomodorosToday(ctx context.Context, userUuid pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countCompletedPomodorosToday, userUuid)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTaskHistory = `-- name: CreateTaskHistory :one
-INSERT INTO task_histories (user_uuid, name, start_time, end_time)
-VALUES ($1, $2, $3, $4)
-RETURNING name,
+INSERT INTO task_histories (user_uuid, name, description, notes, start_time, end_time, mode, interrupted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING uuid, user_uuid, name, description, notes, start_time, end_time, mode, version,
     CONCAT(
         FLOOR(EXTRACT(EPOCH FROM (end_time - start_time)) / 3600), ' hours ',
         FLOOR(EXTRACT(EPOCH FROM (end_time - start_time)) / 60 % 60), ' minutes'
@@ -22,26 +37,340 @@ RETURNING name,
 `
 
 type CreateTaskHistoryParams struct {
-	UserUuid  pgtype.UUID        `json:"user_uuid"`
-	Name      string             `json:"name"`
-	StartTime pgtype.Timestamptz `json:"start_time"`
-	EndTime   pgtype.Timestamptz `json:"end_time"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Interrupted bool               `json:"interrupted"`
 }
 
 type CreateTaskHistoryRow struct {
-	Name     string      `json:"name"`
-	Duration interface{} `json:"duration"`
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+	Duration    interface{}        `json:"duration"`
 }
 
 func (q *Queries) CreateTaskHistory(ctx context.Context, arg CreateTaskHistoryParams) (CreateTaskHistoryRow, error) {
 	row := q.db.QueryRow(ctx, createTaskHistory,
 		arg.UserUuid,
 		arg.Name,
+		arg.Description,
+		arg.Notes,
 		arg.StartTime,
 		arg.EndTime,
+		arg.Mode,
+		arg.Interrupted,
 	)
 	var i CreateTaskHistoryRow
-	err := row.Scan(&i.Name, &i.Duration)
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.Description,
+		&i.Notes,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.Version,
+		&i.Duration,
+	)
+	return i, err
+}
+
+const deleteTaskHistory = `-- name: DeleteTaskHistory :execrows
+DELETE FROM task_histories
+WHERE uuid = $1 AND user_uuid = $2
+`
+
+type DeleteTaskHistoryParams struct {
+	EntryUuid pgtype.UUID `json:"entry_uuid"`
+	UserUuid  pgtype.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) DeleteTaskHistory(ctx context.Context, arg DeleteTaskHistoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTaskHistory, arg.EntryUuid, arg.UserUuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCompletedPomodorosByPeriod = `-- name: GetCompletedPomodorosByPeriod :many
+SELECT
+    DATE(th.end_time AT TIME ZONE 'UTC') AS day,
+    COUNT(*) AS completed
+FROM
+    task_histories th
+WHERE
+    th.user_uuid = $1
+    AND th.mode = 'focus'
+    AND NOT th.interrupted
+    AND th.end_time >= NOW() - CAST($2 AS INTERVAL)
+GROUP BY
+    day
+ORDER BY
+    day
+`
+
+type GetCompletedPomodorosByPeriodParams struct {
+	UserUuid pgtype.UUID     `json:"user_uuid"`
+	Period   pgtype.Interval `json:"period"`
+}
+
+type GetCompletedPomodorosByPeriodRow struct {
+	Day       pgtype.Date `json:"day"`
+	Completed int64       `json:"completed"`
+}
+
+func (q *Queries) GetCompletedPomodorosByPeriod(ctx context.Context, arg GetCompletedPomodorosByPeriodParams) ([]GetCompletedPomodorosByPeriodRow, error) {
+	rows, err := q.db.Query(ctx, getCompletedPomodorosByPeriod, arg.UserUuid, arg.Period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCompletedPomodorosByPeriodRow{}
+	for rows.Next() {
+		var i GetCompletedPomodorosByPeriodRow
+		if err := rows.Scan(&i.Day, &i.Completed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDailyTrackedSeconds = `-- name: GetDailyTrackedSeconds :many
+SELECT
+    DATE(th.start_time AT TIME ZONE 'UTC') AS day,
+    CAST(SUM(EXTRACT(EPOCH FROM (th.end_time - th.start_time))) AS BIGINT) AS tracked_seconds
+FROM
+    task_histories th
+WHERE
+    th.user_uuid = $1
+    AND th.start_time >= $2
+    AND th.start_time < $3
+GROUP BY
+    day
+ORDER BY
+    day
+`
+
+type GetDailyTrackedSecondsParams struct {
+	UserUuid pgtype.UUID        `json:"user_uuid"`
+	FromTime pgtype.Timestamptz `json:"from_time"`
+	ToTime   pgtype.Timestamptz `json:"to_time"`
+}
+
+type GetDailyTrackedSecondsRow struct {
+	Day            pgtype.Date `json:"day"`
+	TrackedSeconds int64       `json:"tracked_seconds"`
+}
+
+func (q *Queries) GetDailyTrackedSeconds(ctx context.Context, arg GetDailyTrackedSecondsParams) ([]GetDailyTrackedSecondsRow, error) {
+	rows, err := q.db.Query(ctx, getDailyTrackedSeconds, arg.UserUuid, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDailyTrackedSecondsRow{}
+	for rows.Next() {
+		var i GetDailyTrackedSecondsRow
+		if err := rows.Scan(&i.Day, &i.TrackedSeconds); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastTaskHistory = `-- name: GetLastTaskHistory :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE user_uuid = $1
+ORDER BY end_time DESC
+LIMIT 1
+`
+
+type GetLastTaskHistoryRow struct {
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+}
+
+func (q *Queries) GetLastTaskHistory(ctx context.Context, userUuid pgtype.UUID) (GetLastTaskHistoryRow, error) {
+	row := q.db.QueryRow(ctx, getLastTaskHistory, userUuid)
+	var i GetLastTaskHistoryRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.Description,
+		&i.Notes,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getLastTaskHistoryEndTime = `-- name: GetLastTaskHistoryEndTime :one
+SELECT MAX(end_time)::timestamptz AS end_time FROM task_histories
+WHERE user_uuid = $1
+`
+
+func (q *Queries) GetLastTaskHistoryEndTime(ctx context.Context, userUuid pgtype.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLastTaskHistoryEndTime, userUuid)
+	var end_time pgtype.Timestamptz
+	err := row.Scan(&end_time)
+	return end_time, err
+}
+
+const getTaskHistoriesByUser = `-- name: GetTaskHistoriesByUser :many
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE user_uuid = $1
+ORDER BY start_time
+`
+
+type GetTaskHistoriesByUserRow struct {
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+}
+
+func (q *Queries) GetTaskHistoriesByUser(ctx context.Context, userUuid pgtype.UUID) ([]GetTaskHistoriesByUserRow, error) {
+	rows, err := q.db.Query(ctx, getTaskHistoriesByUser, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTaskHistoriesByUserRow{}
+	for rows.Next() {
+		var i GetTaskHistoriesByUserRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.UserUuid,
+			&i.Name,
+			&i.Description,
+			&i.Notes,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Mode,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaskHistoryByUUID = `-- name: GetTaskHistoryByUUID :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE uuid = $1 AND user_uuid = $2
+`
+
+type GetTaskHistoryByUUIDParams struct {
+	EntryUuid pgtype.UUID `json:"entry_uuid"`
+	UserUuid  pgtype.UUID `json:"user_uuid"`
+}
+
+type GetTaskHistoryByUUIDRow struct {
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+}
+
+func (q *Queries) GetTaskHistoryByUUID(ctx context.Context, arg GetTaskHistoryByUUIDParams) (GetTaskHistoryByUUIDRow, error) {
+	row := q.db.QueryRow(ctx, getTaskHistoryByUUID, arg.EntryUuid, arg.UserUuid)
+	var i GetTaskHistoryByUUIDRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.Description,
+		&i.Notes,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getTaskHistoryByUUIDForUpdate = `-- name: GetTaskHistoryByUUIDForUpdate :one
+SELECT uuid, user_uuid, name, description, notes, start_time, end_time, mode, version FROM task_histories
+WHERE uuid = $1 AND user_uuid = $2
+FOR UPDATE
+`
+
+type GetTaskHistoryByUUIDForUpdateParams struct {
+	EntryUuid pgtype.UUID `json:"entry_uuid"`
+	UserUuid  pgtype.UUID `json:"user_uuid"`
+}
+
+type GetTaskHistoryByUUIDForUpdateRow struct {
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+}
+
+func (q *Queries) GetTaskHistoryByUUIDForUpdate(ctx context.Context, arg GetTaskHistoryByUUIDForUpdateParams) (GetTaskHistoryByUUIDForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getTaskHistoryByUUIDForUpdate, arg.EntryUuid, arg.UserUuid)
+	var i GetTaskHistoryByUUIDForUpdateRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.Description,
+		&i.Notes,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.Version,
+	)
 	return i, err
 }
 
@@ -49,6 +378,8 @@ const getTasksResultByPeriod = `-- name: GetTasksResultByPeriod :many
 WITH task_durations AS (
     SELECT
         th.name AS task_name,
+        th.description,
+        th.notes,
         EXTRACT(EPOCH FROM (th.end_time - th.start_time)) AS duration_seconds
     FROM
         task_histories th
@@ -57,6 +388,8 @@ WITH task_durations AS (
 )
 SELECT
     td.task_name,
+    td.description,
+    td.notes,
     CONCAT(
         FLOOR(td.duration_seconds / 3600), ' hours ',
         FLOOR((td.duration_seconds / 60) % 60), ' minutes'
@@ -78,6 +411,8 @@ type GetTasksResultByPeriodParams struct {
 
 type GetTasksResultByPeriodRow struct {
 	TaskName      string      `json:"task_name"`
+	Description   pgtype.Text `json:"description"`
+	Notes         pgtype.Text `json:"notes"`
 	Duration      interface{} `json:"duration"`
 	TotalDuration interface{} `json:"total_duration"`
 }
@@ -91,7 +426,13 @@ func (q *Queries) GetTasksResultByPeriod(ctx context.Context, arg GetTasksResult
 	items := []GetTasksResultByPeriodRow{}
 	for rows.Next() {
 		var i GetTasksResultByPeriodRow
-		if err := rows.Scan(&i.TaskName, &i.Duration, &i.TotalDuration); err != nil {
+		if err := rows.Scan(
+			&i.TaskName,
+			&i.Description,
+			&i.Notes,
+			&i.Duration,
+			&i.TotalDuration,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -101,3 +442,216 @@ func (q *Queries) GetTasksResultByPeriod(ctx context.Context, arg GetTasksResult
 	}
 	return items, nil
 }
+
+const searchTaskHistory = `-- name: SearchTaskHistory :many
+SELECT
+    th.uuid,
+    th.user_uuid,
+    th.name,
+    th.description,
+    th.notes,
+    th.start_time,
+    th.end_time,
+    ts_rank(th.search_vector, q.query) AS rank,
+    ts_headline('russian', th.name, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+    ts_headline('russian', concat_ws(' ', th.description, th.notes), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS text_highlight
+FROM
+    task_histories th,
+    websearch_to_tsquery('russian', $1) AS q(query)
+WHERE
+    (th.user_uuid = $2 OR ($3::bool AND th.user_uuid IN (SELECT uuid FROM users WHERE manager_uuid = $2)))
+    AND th.search_vector @@ q.query
+    AND (th.start_time >= $4 OR $4 IS NULL)
+    AND (th.start_time < $5 OR $5 IS NULL)
+ORDER BY
+    rank DESC,
+    th.start_time DESC
+LIMIT $6 OFFSET $7
+`
+
+type SearchTaskHistoryParams struct {
+	Query        string             `json:"query"`
+	UserUuid     pgtype.UUID        `json:"user_uuid"`
+	Team         bool               `json:"team"`
+	FromTime     pgtype.Timestamptz `json:"from_time"`
+	ToTime       pgtype.Timestamptz `json:"to_time"`
+	ResultLimit  int32              `json:"result_limit"`
+	ResultOffset int32              `json:"result_offset"`
+}
+
+type SearchTaskHistoryRow struct {
+	Uuid          pgtype.UUID        `json:"uuid"`
+	UserUuid      pgtype.UUID        `json:"user_uuid"`
+	Name          string             `json:"name"`
+	Description   pgtype.Text        `json:"description"`
+	Notes         pgtype.Text        `json:"notes"`
+	StartTime     pgtype.Timestamptz `json:"start_time"`
+	EndTime       pgtype.Timestamptz `json:"end_time"`
+	Rank          float32            `json:"rank"`
+	NameHighlight string             `json:"name_highlight"`
+	TextHighlight string             `json:"text_highlight"`
+}
+
+func (q *Queries) SearchTaskHistory(ctx context.Context, arg SearchTaskHistoryParams) ([]SearchTaskHistoryRow, error) {
+	rows, err := q.db.Query(ctx, searchTaskHistory,
+		arg.Query,
+		arg.UserUuid,
+		arg.Team,
+		arg.FromTime,
+		arg.ToTime,
+		arg.ResultLimit,
+		arg.ResultOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTaskHistoryRow{}
+	for rows.Next() {
+		var i SearchTaskHistoryRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.UserUuid,
+			&i.Name,
+			&i.Description,
+			&i.Notes,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Rank,
+			&i.NameHighlight,
+			&i.TextHighlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const suggestTaskNames = `-- name: SuggestTaskNames :many
+SELECT
+    th.name,
+    COUNT(*) AS uses,
+    MAX(th.end_time)::timestamptz AS last_used,
+    (
+        word_similarity($1, th.name)
+        + LN(1 + COUNT(*)) / 2
+        + 1 / (1 + EXTRACT(EPOCH FROM (NOW() - MAX(th.end_time))) / 604800)
+    )::float8 AS score
+FROM
+    task_histories th
+WHERE
+    (th.user_uuid = $2 OR ($3::bool AND th.user_uuid IN (SELECT uuid FROM users WHERE manager_uuid = $2)))
+    AND (th.name ILIKE $4 OR $1 <% th.name)
+GROUP BY
+    th.name
+ORDER BY
+    bool_or(th.name ILIKE $4) DESC,
+    score DESC,
+    th.name
+LIMIT $5
+`
+
+type SuggestTaskNamesParams struct {
+	Query       string      `json:"query"`
+	UserUuid    pgtype.UUID `json:"user_uuid"`
+	Team        bool        `json:"team"`
+	Pattern     string      `json:"pattern"`
+	ResultLimit int32       `json:"result_limit"`
+}
+
+type SuggestTaskNamesRow struct {
+	Name     string             `json:"name"`
+	Uses     int64              `json:"uses"`
+	LastUsed pgtype.Timestamptz `json:"last_used"`
+	Score    float64            `json:"score"`
+}
+
+func (q *Queries) SuggestTaskNames(ctx context.Context, arg SuggestTaskNamesParams) ([]SuggestTaskNamesRow, error) {
+	rows, err := q.db.Query(ctx, suggestTaskNames,
+		arg.Query,
+		arg.UserUuid,
+		arg.Team,
+		arg.Pattern,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SuggestTaskNamesRow{}
+	for rows.Next() {
+		var i SuggestTaskNamesRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Uses,
+			&i.LastUsed,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTaskHistory = `-- name: UpdateTaskHistory :one
+UPDATE task_histories
+SET name = coalesce($1, name),
+    description = coalesce($2, description),
+    notes = coalesce($3, notes)
+WHERE uuid = $4 AND user_uuid = $5
+    AND (version = ANY($6::int[]) OR $6 IS NULL)
+RETURNING uuid, user_uuid, name, description, notes, start_time, end_time, mode, version
+`
+
+type UpdateTaskHistoryParams struct {
+	Name        pgtype.Text `json:"name"`
+	Description pgtype.Text `json:"description"`
+	Notes       pgtype.Text `json:"notes"`
+	EntryUuid   pgtype.UUID `json:"entry_uuid"`
+	UserUuid    pgtype.UUID `json:"user_uuid"`
+	Versions    []int32     `json:"versions"`
+}
+
+type UpdateTaskHistoryRow struct {
+	Uuid        pgtype.UUID        `json:"uuid"`
+	UserUuid    pgtype.UUID        `json:"user_uuid"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Notes       pgtype.Text        `json:"notes"`
+	StartTime   pgtype.Timestamptz `json:"start_time"`
+	EndTime     pgtype.Timestamptz `json:"end_time"`
+	Mode        string             `json:"mode"`
+	Version     int32              `json:"version"`
+}
+
+func (q *Queries) UpdateTaskHistory(ctx context.Context, arg UpdateTaskHistoryParams) (UpdateTaskHistoryRow, error) {
+	row := q.db.QueryRow(ctx, updateTaskHistory,
+		arg.Name,
+		arg.Description,
+		arg.Notes,
+		arg.EntryUuid,
+		arg.UserUuid,
+		arg.Versions,
+	)
+	var i UpdateTaskHistoryRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.Description,
+		&i.Notes,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.Version,
+	)
+	return i, err
+}
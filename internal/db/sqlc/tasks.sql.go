@@ -12,18 +12,35 @@ import (
 )
 
 const createTask = `-- name: CreateTask :one
-INSERT INTO tasks (user_uuid, name)
-VALUES ($1, $2)
-RETURNING uuid, user_uuid, name, start_time, end_time
+INSERT INTO tasks (user_uuid, name, description, notes, mode, start_time, planned_end_time)
+VALUES (
+    $1, $2, $3, $4, $5,
+    COALESCE($6, NOW()),
+    COALESCE($6, NOW()) + make_interval(mins => $7)
+)
+RETURNING uuid, user_uuid, name, start_time, end_time, mode, planned_end_time, description, notes
 `
 
 type CreateTaskParams struct {
-	UserUuid pgtype.UUID `json:"user_uuid"`
-	Name     string      `json:"name"`
+	UserUuid       pgtype.UUID        `json:"user_uuid"`
+	Name           string             `json:"name"`
+	Description    pgtype.Text        `json:"description"`
+	Notes          pgtype.Text        `json:"notes"`
+	Mode           string             `json:"mode"`
+	StartTime      pgtype.Timestamptz `json:"start_time"`
+	PlannedMinutes pgtype.Int4        `json:"planned_minutes"`
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
-	row := q.db.QueryRow(ctx, createTask, arg.UserUuid, arg.Name)
+	row := q.db.QueryRow(ctx, createTask,
+		arg.UserUuid,
+		arg.Name,
+		arg.Description,
+		arg.Notes,
+		arg.Mode,
+		arg.StartTime,
+		arg.PlannedMinutes,
+	)
 	var i Task
 	err := row.Scan(
 		&i.Uuid,
@@ -31,10 +48,50 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.Name,
 		&i.StartTime,
 		&i.EndTime,
+		&i.Mode,
+		&i.PlannedEndTime,
+		&i.Description,
+		&i.Notes,
 	)
 	return i, err
 }
 
+const deleteExpiredTasks = `-- name: DeleteExpiredTasks :many
+DELETE FROM tasks
+WHERE planned_end_time <= NOW()
+RETURNING uuid, user_uuid, name, start_time, end_time, mode, planned_end_time, description, notes
+`
+
+func (q *Queries) DeleteExpiredTasks(ctx context.Context) ([]Task, error) {
+	rows, err := q.db.Query(ctx, deleteExpiredTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.UserUuid,
+			&i.Name,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Mode,
+			&i.PlannedEndTime,
+			&i.Description,
+			&i.Notes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteTask = `-- name: DeleteTask :exec
 DELETE FROM tasks
 WHERE user_uuid = $1
@@ -45,15 +102,45 @@ func (q *Queries) DeleteTask(ctx context.Context, userUuid pgtype.UUID) error {
 	return err
 }
 
+const getActiveTask = `-- name: GetActiveTask :one
+SELECT uuid, user_uuid, name, start_time, end_time, mode, planned_end_time, description, notes FROM tasks
+WHERE user_uuid = $1
+`
+
+func (q *Queries) GetActiveTask(ctx context.Context, userUuid pgtype.UUID) (Task, error) {
+	row := q.db.QueryRow(ctx, getActiveTask, userUuid)
+	var i Task
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Mode,
+		&i.PlannedEndTime,
+		&i.Description,
+		&i.Notes,
+	)
+	return i, err
+}
+
 const updateTaskEndTime = `-- name: UpdateTaskEndTime :one
 UPDATE tasks
-SET end_time = NOW()
-WHERE user_uuid = $1
-RETURNING uuid, user_uuid, name, start_time, end_time
+SET end_time = LEAST(
+    COALESCE($1, NOW()),
+    COALESCE(planned_end_time, $1, NOW())
+)
+WHERE user_uuid = $2
+RETURNING uuid, user_uuid, name, start_time, end_time, mode, planned_end_time, description, notes
 `
 
-func (q *Queries) UpdateTaskEndTime(ctx context.Context, userUuid pgtype.UUID) (Task, error) {
-	row := q.db.QueryRow(ctx, updateTaskEndTime, userUuid)
+type UpdateTaskEndTimeParams struct {
+	EndTime  pgtype.Timestamptz `json:"end_time"`
+	UserUuid pgtype.UUID        `json:"user_uuid"`
+}
+
+func (q *Queries) UpdateTaskEndTime(ctx context.Context, arg UpdateTaskEndTimeParams) (Task, error) {
+	row := q.db.QueryRow(ctx, updateTaskEndTime, arg.EndTime, arg.UserUuid)
 	var i Task
 	err := row.Scan(
 		&i.Uuid,
@@ -61,6 +148,10 @@ func (q *Queries) UpdateTaskEndTime(ctx context.Context, userUuid pgtype.UUID) (
 		&i.Name,
 		&i.StartTime,
 		&i.EndTime,
+		&i.Mode,
+		&i.PlannedEndTime,
+		&i.Description,
+		&i.Notes,
 	)
 	return i, err
 }
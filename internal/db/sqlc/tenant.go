@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TenantSetting is the session setting read by the row-level security
+// policies to restrict rows to one organization.
+const TenantSetting = "app.organization_uuid"
+
+type organizationKey struct{}
+
+// WithOrganization returns a copy of ctx whose queries only see the rows of
+// the organization organizationUUID.
+func WithOrganization(ctx context.Context, organizationUUID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationUUID)
+}
+
+// OrganizationFromContext returns the organization stored by WithOrganization.
+func OrganizationFromContext(ctx context.Context) (uuid.UUID, bool) {
+	organizationUUID, ok := ctx.Value(organizationKey{}).(uuid.UUID)
+	return organizationUUID, ok
+}
+
+// ConfigureTenantIsolation sets TenantSetting on every connection acquired
+// from the pool, to the organization of the acquiring context or to nothing
+// for contexts without one, which then see no rows unless the role bypasses
+// row-level security.
+func ConfigureTenantIsolation(config *pgxpool.Config) {
+	beforeAcquire := config.BeforeAcquire
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if beforeAcquire != nil && !beforeAcquire(ctx, conn) {
+			return false
+		}
+
+		var tenant string
+		if organizationUUID, ok := OrganizationFromContext(ctx); ok {
+			tenant = organizationUUID.String()
+		}
+
+		// A connection whose setting cannot be changed must not be reused with
+		// the tenant of its previous user.
+		_, err := conn.Exec(ctx, "SELECT set_config($1, $2, false)", TenantSetting, tenant)
+		return err == nil
+	}
+}
@@ -11,27 +11,80 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeUserByUUID = `-- name: AnonymizeUserByUUID :one
+UPDATE users
+SET surname = $1,
+    name = $1,
+    patronymic = NULL,
+    address = $1,
+    passport_number = NULL,
+    passport_number_ciphertext = NULL,
+    passport_number_index = NULL,
+    email = NULL,
+    anonymized_at = CURRENT_TIMESTAMP,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE uuid = $2
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
+`
+
+type AnonymizeUserByUUIDParams struct {
+	Placeholder string      `json:"placeholder"`
+	UserUuid    pgtype.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) AnonymizeUserByUUID(ctx context.Context, arg AnonymizeUserByUUIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, anonymizeUserByUUID, arg.Placeholder, arg.UserUuid)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.PassportNumber,
+		&i.Surname,
+		&i.Name,
+		&i.Patronymic,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (passport_number, surname, name, patronymic, address)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at
+INSERT INTO users (organization_uuid, passport_number_ciphertext, passport_number_index, surname, name, patronymic, address, email)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
 `
 
 type CreateUserParams struct {
-	PassportNumber string      `json:"passport_number"`
-	Surname        string      `json:"surname"`
-	Name           string      `json:"name"`
-	Patronymic     pgtype.Text `json:"patronymic"`
-	Address        string      `json:"address"`
+	OrganizationUuid         pgtype.UUID `json:"organization_uuid"`
+	PassportNumberCiphertext pgtype.Text `json:"passport_number_ciphertext"`
+	PassportNumberIndex      pgtype.Text `json:"passport_number_index"`
+	Surname                  string      `json:"surname"`
+	Name                     string      `json:"name"`
+	Patronymic               pgtype.Text `json:"patronymic"`
+	Address                  string      `json:"address"`
+	Email                    pgtype.Text `json:"email"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	row := q.db.QueryRow(ctx, createUser,
-		arg.PassportNumber,
+		arg.OrganizationUuid,
+		arg.PassportNumberCiphertext,
+		arg.PassportNumberIndex,
 		arg.Surname,
 		arg.Name,
 		arg.Patronymic,
 		arg.Address,
+		arg.Email,
 	)
 	var i User
 	err := row.Scan(
@@ -43,27 +96,68 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Address,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
 
-const deleteUserByUUID = `-- name: DeleteUserByUUID :exec
-DELETE FROM users
+const deactivateUserByUUID = `-- name: DeactivateUserByUUID :one
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP
 WHERE uuid = $1
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
 `
 
-func (q *Queries) DeleteUserByUUID(ctx context.Context, userUuid pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUserByUUID, userUuid)
-	return err
+func (q *Queries) DeactivateUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, deactivateUserByUUID, userUuid)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.PassportNumber,
+		&i.Surname,
+		&i.Name,
+		&i.Patronymic,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
 }
 
 const getUserByPassportNumber = `-- name: GetUserByPassportNumber :one
-SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at FROM users
-WHERE passport_number = $1
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
+WHERE organization_uuid = $1
+    AND (passport_number_index = ANY($2::text[]) OR passport_number = $3)
+LIMIT 1
 `
 
-func (q *Queries) GetUserByPassportNumber(ctx context.Context, passportNumber string) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByPassportNumber, passportNumber)
+type GetUserByPassportNumberParams struct {
+	OrganizationUuid      pgtype.UUID `json:"organization_uuid"`
+	PassportNumberIndexes []string    `json:"passport_number_indexes"`
+	PassportNumber        pgtype.Text `json:"passport_number"`
+}
+
+func (q *Queries) GetUserByPassportNumber(ctx context.Context, arg GetUserByPassportNumberParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByPassportNumber, arg.OrganizationUuid, arg.PassportNumberIndexes, arg.PassportNumber)
 	var i User
 	err := row.Scan(
 		&i.Uuid,
@@ -74,12 +168,22 @@ func (q *Queries) GetUserByPassportNumber(ctx context.Context, passportNumber st
 		&i.Address,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
 
 const getUserByUUID = `-- name: GetUserByUUID :one
-SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at FROM users
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
 WHERE uuid = $1
 `
 
@@ -95,38 +199,90 @@ func (q *Queries) GetUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User
 		&i.Address,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
+const getUserByUUIDForUpdate = `-- name: GetUserByUUIDForUpdate :one
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
+WHERE uuid = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetUserByUUIDForUpdate(ctx context.Context, userUuid pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByUUIDForUpdate, userUuid)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.PassportNumber,
+		&i.Surname,
+		&i.Name,
+		&i.Patronymic,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at FROM users
-WHERE
-    (passport_number = $1 OR $1 IS NULL)
-    AND (surname = $2 OR $2 IS NULL)
-    AND (name = $3 OR $3 IS NULL)
-    AND (patronymic = $4 OR $4 IS NULL)
-    AND (address = $5 OR $5 IS NULL)
-LIMIT $7 OFFSET $6
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
+WHERE organization_uuid = $1
+    AND (passport_number_index = ANY($2::text[]) OR passport_number = $3 OR $3::text IS NULL)
+    AND (surname = $4 OR $4 IS NULL)
+    AND (name = $5 OR $5 IS NULL)
+    AND (patronymic = $6 OR $6 IS NULL)
+    AND (address = $7 OR $7 IS NULL)
+    AND (manager_uuid = $8 OR uuid = $8 OR $8::uuid IS NULL)
+    AND (deleted_at IS NULL OR $9::bool)
+LIMIT $11 OFFSET $10
 `
 
 type GetUsersParams struct {
-	PassportNumber pgtype.Text `json:"passport_number"`
-	Surname        pgtype.Text `json:"surname"`
-	Name           pgtype.Text `json:"name"`
-	Patronymic     pgtype.Text `json:"patronymic"`
-	Address        pgtype.Text `json:"address"`
-	UserOffset     int32       `json:"user_offset"`
-	UserLimit      int32       `json:"user_limit"`
+	OrganizationUuid      pgtype.UUID `json:"organization_uuid"`
+	PassportNumberIndexes []string    `json:"passport_number_indexes"`
+	PassportNumber        pgtype.Text `json:"passport_number"`
+	Surname               pgtype.Text `json:"surname"`
+	Name                  pgtype.Text `json:"name"`
+	Patronymic            pgtype.Text `json:"patronymic"`
+	Address               pgtype.Text `json:"address"`
+	TeamOf                pgtype.UUID `json:"team_of"`
+	IncludeDeleted        bool        `json:"include_deleted"`
+	UserOffset            int32       `json:"user_offset"`
+	UserLimit             int32       `json:"user_limit"`
 }
 
 func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error) {
 	rows, err := q.db.Query(ctx, getUsers,
+		arg.OrganizationUuid,
+		arg.PassportNumberIndexes,
 		arg.PassportNumber,
 		arg.Surname,
 		arg.Name,
 		arg.Patronymic,
 		arg.Address,
+		arg.TeamOf,
+		arg.IncludeDeleted,
 		arg.UserOffset,
 		arg.UserLimit,
 	)
@@ -146,6 +302,112 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.Address,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.Role,
+			&i.ManagerUuid,
+			&i.OrganizationUuid,
+			&i.Email,
+			&i.PassportNumberCiphertext,
+			&i.PassportNumberIndex,
+			&i.Permissions,
+			&i.DeletedAt,
+			&i.AnonymizedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByEmail = `-- name: GetUsersByEmail :many
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
+WHERE lower(email) = lower($1::text)
+    AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUsersByEmail(ctx context.Context, email string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.PassportNumber,
+			&i.Surname,
+			&i.Name,
+			&i.Patronymic,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Role,
+			&i.ManagerUuid,
+			&i.OrganizationUuid,
+			&i.Email,
+			&i.PassportNumberCiphertext,
+			&i.PassportNumberIndex,
+			&i.Permissions,
+			&i.DeletedAt,
+			&i.AnonymizedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersPage = `-- name: GetUsersPage :many
+SELECT uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at FROM users
+WHERE uuid > $1
+ORDER BY uuid
+LIMIT $2
+`
+
+type GetUsersPageParams struct {
+	AfterUuid pgtype.UUID `json:"after_uuid"`
+	PageSize  int32       `json:"page_size"`
+}
+
+func (q *Queries) GetUsersPage(ctx context.Context, arg GetUsersPageParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersPage, arg.AfterUuid, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.PassportNumber,
+			&i.Surname,
+			&i.Name,
+			&i.Patronymic,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Role,
+			&i.ManagerUuid,
+			&i.OrganizationUuid,
+			&i.Email,
+			&i.PassportNumberCiphertext,
+			&i.PassportNumberIndex,
+			&i.Permissions,
+			&i.DeletedAt,
+			&i.AnonymizedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -157,24 +419,144 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 	return items, nil
 }
 
+const purgeUserByUUID = `-- name: PurgeUserByUUID :exec
+DELETE FROM users
+WHERE uuid = $1
+`
+
+func (q *Queries) PurgeUserByUUID(ctx context.Context, userUuid pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, purgeUserByUUID, userUuid)
+	return err
+}
+
+const restoreUserByUUID = `-- name: RestoreUserByUUID :one
+UPDATE users
+SET deleted_at = NULL
+WHERE uuid = $1
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
+`
+
+func (q *Queries) RestoreUserByUUID(ctx context.Context, userUuid pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUserByUUID, userUuid)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.PassportNumber,
+		&i.Surname,
+		&i.Name,
+		&i.Patronymic,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
+const setUserPassportNumber = `-- name: SetUserPassportNumber :exec
+UPDATE users
+SET passport_number = $1,
+    passport_number_ciphertext = $2,
+    passport_number_index = $3
+WHERE uuid = $4
+`
+
+type SetUserPassportNumberParams struct {
+	PassportNumber           pgtype.Text `json:"passport_number"`
+	PassportNumberCiphertext pgtype.Text `json:"passport_number_ciphertext"`
+	PassportNumberIndex      pgtype.Text `json:"passport_number_index"`
+	UserUuid                 pgtype.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) SetUserPassportNumber(ctx context.Context, arg SetUserPassportNumberParams) error {
+	_, err := q.db.Exec(ctx, setUserPassportNumber,
+		arg.PassportNumber,
+		arg.PassportNumberCiphertext,
+		arg.PassportNumberIndex,
+		arg.UserUuid,
+	)
+	return err
+}
+
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users
+SET role = $1,
+    manager_uuid = $2,
+    permissions = $3
+WHERE uuid = $4
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
+`
+
+type SetUserRoleParams struct {
+	Role        string      `json:"role"`
+	ManagerUuid pgtype.UUID `json:"manager_uuid"`
+	Permissions []string    `json:"permissions"`
+	UserUuid    pgtype.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserRole,
+		arg.Role,
+		arg.ManagerUuid,
+		arg.Permissions,
+		arg.UserUuid,
+	)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.PassportNumber,
+		&i.Surname,
+		&i.Name,
+		&i.Patronymic,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
 const updateUserByUUID = `-- name: UpdateUserByUUID :one
 UPDATE users
-SET surname = coalesce($1, surname),
-    name = coalesce($2, name),
-    patronymic = coalesce($3, patronymic),
-    address = coalesce($4, address),
-    passport_number = coalesce($5, passport_number)
-WHERE uuid = $6
-RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at
+SET surname = $1,
+    name = $2,
+    patronymic = $3,
+    address = $4,
+    passport_number = NULL,
+    passport_number_ciphertext = $5,
+    passport_number_index = $6,
+    email = $7
+WHERE uuid = $8
+RETURNING uuid, passport_number, surname, name, patronymic, address, created_at, updated_at, version, role, manager_uuid, organization_uuid, email, passport_number_ciphertext, passport_number_index, permissions, deleted_at, anonymized_at
 `
 
 type UpdateUserByUUIDParams struct {
-	Surname        pgtype.Text `json:"surname"`
-	Name           pgtype.Text `json:"name"`
-	Patronymic     pgtype.Text `json:"patronymic"`
-	Address        pgtype.Text `json:"address"`
-	PassportNumber pgtype.Text `json:"passport_number"`
-	UserUuid       pgtype.UUID `json:"user_uuid"`
+	Surname                  string      `json:"surname"`
+	Name                     string      `json:"name"`
+	Patronymic               pgtype.Text `json:"patronymic"`
+	Address                  string      `json:"address"`
+	PassportNumberCiphertext pgtype.Text `json:"passport_number_ciphertext"`
+	PassportNumberIndex      pgtype.Text `json:"passport_number_index"`
+	Email                    pgtype.Text `json:"email"`
+	UserUuid                 pgtype.UUID `json:"user_uuid"`
 }
 
 func (q *Queries) UpdateUserByUUID(ctx context.Context, arg UpdateUserByUUIDParams) (User, error) {
@@ -183,7 +565,9 @@ func (q *Queries) UpdateUserByUUID(ctx context.Context, arg UpdateUserByUUIDPara
 		arg.Name,
 		arg.Patronymic,
 		arg.Address,
-		arg.PassportNumber,
+		arg.PassportNumberCiphertext,
+		arg.PassportNumberIndex,
+		arg.Email,
 		arg.UserUuid,
 	)
 	var i User
@@ -196,6 +580,16 @@ func (q *Queries) UpdateUserByUUID(ctx context.Context, arg UpdateUserByUUIDPara
 		&i.Address,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.Role,
+		&i.ManagerUuid,
+		&i.OrganizationUuid,
+		&i.Email,
+		&i.PassportNumberCiphertext,
+		&i.PassportNumberIndex,
+		&i.Permissions,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
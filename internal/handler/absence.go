@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var validAbsenceTypes = map[string]interface{}{
+	models.AbsenceTypeVacation:  nil,
+	models.AbsenceTypeSickLeave: nil,
+	models.AbsenceTypePersonal:  nil,
+	models.AbsenceTypeUnpaid:    nil,
+}
+
+// @Summary      Record an absence
+// @Description  Record a vacation, sick leave, personal or unpaid absence of a user. Half days are allowed on the first and last day.
+// @Tags         absences
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "User id"
+// @Param        payload  body      models.CreateAbsencePayload  true  "Absence Payload"
+// @Success      201      {object}  models.Absence               "Absence recorded successfully"
+// @Failure      400      {object}  errorResponse                "Bad request"
+// @Failure      403      {object}  errorResponse                "Forbidden"
+// @Failure      404      {object}  errorResponse                "User not found"
+// @Failure      409      {object}  errorResponse                "Absence overlaps an existing absence"
+// @Failure      500      {object}  errorResponse                "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/absences [post]
+func (h *Handler) CreateAbsence(c *gin.Context) {
+	var payload models.CreateAbsencePayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	startDate, endDate, err := validateCreateAbsencePayload(&payload)
+	if err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	absence, err := h.service.IAbsenceService.CreateAbsence(ctx, userUUID, startDate, endDate, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, service.ErrAbsenceOverlaps) {
+			logrus.Warnf("Absence of user UUID %s overlaps an existing one", userUUID)
+			newErrorResponse(c, http.StatusConflict, "Absence overlaps an existing absence")
+			return
+		}
+		logrus.Errorf("Error recording absence: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Absence recorded successfully: %v", absence)
+	c.JSON(http.StatusCreated, absence)
+}
+
+// @Summary      Get absences
+// @Description  Retrieve the absences of a user overlapping a year
+// @Tags         absences
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string          true   "User id"
+// @Param        year  query     int             false  "Year, defaults to the current year"
+// @Success      200   {array}   models.Absence  "Absences retrieved successfully"
+// @Failure      400   {object}  errorResponse   "Bad request"
+// @Failure      403   {object}  errorResponse   "Forbidden"
+// @Failure      404   {object}  errorResponse   "User not found"
+// @Failure      500   {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/absences [get]
+func (h *Handler) GetAbsences(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	year, err := parseYear(c)
+	if err != nil {
+		logrus.Errorf("Invalid year: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	absences, err := h.service.IAbsenceService.GetAbsences(ctx, userUUID, year)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error retrieving absences: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, absences)
+}
+
+// @Summary      Delete an absence
+// @Description  Delete an absence of a user by its id
+// @Tags         absences
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string          true  "User id"
+// @Param        absenceId  path      string          true  "Absence id"
+// @Success      200        {object}  statusResponse  "Absence deleted successfully"
+// @Failure      400        {object}  errorResponse   "Bad request"
+// @Failure      403        {object}  errorResponse   "Forbidden"
+// @Failure      404        {object}  errorResponse   "Absence not found"
+// @Failure      500        {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/absences/{absenceId} [delete]
+func (h *Handler) DeleteAbsence(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	absenceUUID, err := uuid.Parse(c.Param("absenceId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IAbsenceService.DeleteAbsence(ctx, userUUID, absenceUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrAbsenceNotFound) {
+			logrus.Infof("No absence %s found for user UUID: %s", absenceUUID, userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Absence not found")
+			return
+		}
+		logrus.Errorf("Error deleting absence: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Absence deleted successfully: UUID=%s", absenceUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "Absence deleted successfully"})
+}
+
+// @Summary      Set an absence allowance
+// @Description  Create or replace the number of days a user may take of an absence type in a year
+// @Tags         absences
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "User id"
+// @Param        payload  body      models.AbsenceBalancePayload  true  "Absence Balance Payload"
+// @Success      200      {object}  statusResponse                "Allowance saved successfully"
+// @Failure      400      {object}  errorResponse                 "Bad request"
+// @Failure      403      {object}  errorResponse                 "Forbidden"
+// @Failure      404      {object}  errorResponse                 "User not found"
+// @Failure      500      {object}  errorResponse                 "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/absences/balances [put]
+func (h *Handler) SetAbsenceBalance(c *gin.Context) {
+	var payload models.AbsenceBalancePayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if _, isValid := validAbsenceTypes[payload.Type]; !isValid || payload.Year <= 0 || payload.AllowanceDays < 0 {
+		logrus.Errorf("Invalid absence balance: %+v", payload)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IAbsenceService.SetAbsenceBalance(ctx, userUUID, &payload); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error setting absence balance: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Description: "Allowance saved successfully"})
+}
+
+// @Summary      Get absence balances
+// @Description  Retrieve allowance, used and remaining days per absence type of a user in a year. Only working days count as used.
+// @Tags         absences
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                  true   "User id"
+// @Param        year  query     int                     false  "Year, defaults to the current year"
+// @Success      200   {array}   models.AbsenceBalance   "Balances retrieved successfully"
+// @Failure      400   {object}  errorResponse           "Bad request"
+// @Failure      403   {object}  errorResponse           "Forbidden"
+// @Failure      404   {object}  errorResponse           "User not found"
+// @Failure      500   {object}  errorResponse           "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/absences/balances [get]
+func (h *Handler) GetAbsenceBalances(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	year, err := parseYear(c)
+	if err != nil {
+		logrus.Errorf("Invalid year: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	balances, err := h.service.IAbsenceService.GetAbsenceBalances(ctx, userUUID, year)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error retrieving absence balances: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+// parseYear reads the year query parameter, defaulting to the current year.
+func parseYear(c *gin.Context) (int, error) {
+	value := c.Query("year")
+	if value == "" {
+		return time.Now().UTC().Year(), nil
+	}
+
+	year, err := strconv.Atoi(value)
+	if err != nil || year <= 0 {
+		return 0, fmt.Errorf("invalid year value")
+	}
+
+	return year, nil
+}
+
+func validateCreateAbsencePayload(payload *models.CreateAbsencePayload) (time.Time, time.Time, error) {
+	if _, isValid := validAbsenceTypes[payload.Type]; !isValid {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid absence type %q", payload.Type)
+	}
+
+	startDate, err := time.Parse(time.DateOnly, payload.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("startDate must be a YYYY-MM-DD date")
+	}
+
+	endDate, err := time.Parse(time.DateOnly, payload.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("endDate must be a YYYY-MM-DD date")
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("endDate is before startDate")
+	}
+	if startDate.Equal(endDate) && payload.HalfDayStart && payload.HalfDayEnd {
+		return time.Time{}, time.Time{}, fmt.Errorf("a single day cannot be half day at both ends")
+	}
+
+	return startDate, endDate, nil
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RequestIDHeader      = "X-Request-ID"
+	RequestIDMaxLength   = 255
+	AuditEntriesMaxLimit = 500
+)
+
+// RequestInfo identifies every request, with the X-Request-ID header of the
+// client or a generated id echoed in the response, and stores the id and the
+// client address in the request context for the audit log.
+func (h *Handler) RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > RequestIDMaxLength {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := service.WithRequestInfo(c.Request.Context(), service.RequestInfo{
+			ID:       requestID,
+			SourceIP: c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// @Summary Get audit entries
+// @Tags audit
+// @Description List the changes to users, tasks and history entries of the organization, latest first. Only admins may read the audit log.
+// @Accept  json
+// @Produce  json
+// @Param entity query string false "Changed entity" Enums(user, task, task_history)
+// @Param entityId query string false "Changed entity id"
+// @Param userId query string false "User the changed entity belongs to"
+// @Param actor query string false "Subject of the caller who made the change"
+// @Param action query string false "Kind of change" Enums(create, update, delete, restore, purge, anonymize)
+// @Param from query string false "Earliest change time (RFC 3339)"
+// @Param to query string false "Change time before which entries are returned (RFC 3339)"
+// @Param limit query int false "Limit the number of entries returned" default(50)
+// @Param offset query int false "Offset the number of entries returned" default(0)
+// @Success 200 {array} models.AuditEntry "Audit entries retrieved successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /audit-log [get]
+func (h *Handler) GetAuditEntries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > AuditEntriesMaxLimit {
+		logrus.Errorf("Invalid limit parameter: %q", c.Query("limit"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		logrus.Errorf("Invalid offset parameter: %q", c.Query("offset"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		logrus.Errorf("Invalid audit filter: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	entries, err := h.service.IAuditService.GetAuditEntries(ctx, filter, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		logrus.Errorf("Error retrieving audit entries: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func parseAuditFilter(c *gin.Context) (*models.AuditFilter, error) {
+	var filter models.AuditFilter
+
+	if entity := c.Query("entity"); entity != "" {
+		switch entity {
+		case models.AuditEntityUser, models.AuditEntityTask, models.AuditEntityTaskHistory:
+			filter.Entity = &entity
+		default:
+			return nil, errors.New("unknown entity")
+		}
+	}
+
+	if action := c.Query("action"); action != "" {
+		switch action {
+		case models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete,
+			models.AuditActionRestore, models.AuditActionPurge, models.AuditActionAnonymize:
+			filter.Action = &action
+		default:
+			return nil, errors.New("unknown action")
+		}
+	}
+
+	if actor := c.Query("actor"); actor != "" {
+		filter.Actor = &actor
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **uuid.UUID
+	}{
+		{"entityId", &filter.EntityUUID},
+		{"userId", &filter.UserUUID},
+	} {
+		if value := c.Query(param.name); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				return nil, err
+			}
+			*param.value = &parsed
+		}
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		if value := c.Query(param.name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, err
+			}
+			*param.value = &parsed
+		}
+	}
+
+	return &filter, nil
+}
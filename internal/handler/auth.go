@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	APIKeyHeader        = "X-API-Key"
+	APIKeyNameMaxLength = 100
+	// AccessReasonHeader carries the reason for reading personal data, kept in
+	// the PII access log.
+	AccessReasonHeader = "X-Access-Reason"
+)
+
+// Authenticate rejects requests without a valid bearer JWT or API key and
+// stores the caller in the request context for the service layer.
+func (h *Handler) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(APIKeyHeader)
+		if authorization := c.GetHeader("Authorization"); token == "" && authorization != "" {
+			scheme, credentials, found := strings.Cut(authorization, " ")
+			if found && strings.EqualFold(scheme, "Bearer") {
+				token = strings.TrimSpace(credentials)
+			}
+		}
+
+		if token == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="time-tracker"`)
+			newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		ctx := c.Request.Context()
+		principal, err := h.service.IAuthService.Authenticate(ctx, token)
+		if err != nil {
+			if errors.Is(err, service.ErrUnauthenticated) {
+				c.Header("WWW-Authenticate", `Bearer realm="time-tracker", error="invalid_token"`)
+				newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			logrus.Errorf("Error authenticating request: %v", err)
+			newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		ctx = service.WithPrincipal(ctx, principal)
+		if reason := c.GetHeader(AccessReasonHeader); reason != "" {
+			ctx = service.WithAccessReason(ctx, reason)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// @Summary      Get the current principal
+// @Description  Return the caller identified by the bearer token or API key
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.Principal  "Principal retrieved successfully"
+// @Failure      401  {object}  errorResponse     "Unauthorized"
+// @Security     BearerAuth
+// @Router       /auth/me [get]
+func (h *Handler) GetPrincipal(c *gin.Context) {
+	principal, ok := service.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	c.JSON(http.StatusOK, principal)
+}
+
+// @Summary      Issue an API key
+// @Description  Create a long-lived key acting as the caller, for integrations. The key is only returned in this response.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.CreateAPIKeyPayload  true  "Create API Key Payload"
+// @Success      201      {object}  models.CreatedAPIKey        "API key created successfully"
+// @Failure      400      {object}  errorResponse               "Bad request"
+// @Failure      401      {object}  errorResponse               "Unauthorized"
+// @Failure      500      {object}  errorResponse               "Internal server error"
+// @Security     BearerAuth
+// @Router       /auth/api-keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var payload models.CreateAPIKeyPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if payload.Name == "" || len([]rune(payload.Name)) > APIKeyNameMaxLength {
+		logrus.Errorf("Invalid API key name: %q", payload.Name)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+		logrus.Errorf("API key expiry in the past: %s", payload.ExpiresAt)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	created, err := h.service.IAuthService.CreateAPIKey(ctx, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrUnauthenticated) {
+			newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		logrus.Errorf("Error creating API key: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("API key created: UUID=%s", created.APIKey.UUID)
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary      Get API keys
+// @Description  List the API keys issued by the caller, without their secrets
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.APIKey  "API keys retrieved successfully"
+// @Failure      401  {object}  errorResponse  "Unauthorized"
+// @Failure      500  {object}  errorResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /auth/api-keys [get]
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+	keys, err := h.service.IAuthService.GetAPIKeys(ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrUnauthenticated) {
+			newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		logrus.Errorf("Error retrieving API keys: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary      Revoke an API key
+// @Description  Disable an API key issued by the caller
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        keyId  path      string          true  "API key id"
+// @Success      200    {object}  statusResponse  "API key revoked successfully"
+// @Failure      400    {object}  errorResponse   "Bad request"
+// @Failure      401    {object}  errorResponse   "Unauthorized"
+// @Failure      404    {object}  errorResponse   "API key not found"
+// @Failure      500    {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /auth/api-keys/{keyId} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	keyUUID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IAuthService.RevokeAPIKey(ctx, keyUUID); err != nil {
+		if errors.Is(err, service.ErrUnauthenticated) {
+			newErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			logrus.Infof("No API key found for UUID: %s", keyUUID)
+			newErrorResponse(c, http.StatusNotFound, "API key not found")
+			return
+		}
+		logrus.Errorf("Error revoking API key: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("API key revoked: UUID=%s", keyUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "API key revoked successfully"})
+}
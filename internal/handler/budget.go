@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary      Set a task budget
+// @Description  Create or update the estimated duration for a user's task
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "User id"
+// @Param        payload  body      models.TaskBudgetPayload  true  "Budget Payload"
+// @Success      200      {object}  models.TaskBudget         "Budget saved successfully"
+// @Failure      400      {object}  errorResponse             "Bad request"
+// @Failure      403      {object}  errorResponse             "Forbidden"
+// @Failure      404      {object}  errorResponse             "User not found"
+// @Failure      500      {object}  errorResponse             "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/budgets [put]
+func (h *Handler) SetTaskBudget(c *gin.Context) {
+	var payload models.TaskBudgetPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err := validateTaskBudgetPayload(&payload); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	budget, err := h.service.IBudgetService.SetTaskBudget(ctx, userUUID, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error setting budget: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Budget saved successfully: %v", budget)
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Summary      Get task budgets
+// @Description  Retrieve the estimate, actual and remaining time of every budget of a user
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string             true  "User id"
+// @Success      200  {array}   models.TaskBudget  "Budgets retrieved successfully"
+// @Failure      400  {object}  errorResponse      "Bad request"
+// @Failure      403  {object}  errorResponse      "Forbidden"
+// @Failure      404  {object}  errorResponse      "User not found"
+// @Failure      500  {object}  errorResponse      "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/budgets [get]
+func (h *Handler) GetTaskBudgets(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	budgets, err := h.service.IBudgetService.GetTaskBudgets(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error retrieving budgets: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// @Summary      Delete a task budget
+// @Description  Delete a budget of a user by its id
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string          true  "User id"
+// @Param        budgetId  path      string          true  "Budget id"
+// @Success      200       {object}  statusResponse  "Budget deleted successfully"
+// @Failure      400       {object}  errorResponse   "Bad request"
+// @Failure      403       {object}  errorResponse   "Forbidden"
+// @Failure      404       {object}  errorResponse   "Budget not found"
+// @Failure      500       {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/budgets/{budgetId} [delete]
+func (h *Handler) DeleteTaskBudget(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	budgetUUID, err := uuid.Parse(c.Param("budgetId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IBudgetService.DeleteTaskBudget(ctx, userUUID, budgetUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrBudgetNotFound) {
+			logrus.Infof("No budget %s found for user UUID: %s", budgetUUID, userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Budget not found")
+			return
+		}
+		logrus.Errorf("Error deleting budget: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Budget deleted successfully: UUID=%s", budgetUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "Budget deleted successfully"})
+}
+
+func validateTaskBudgetPayload(payload *models.TaskBudgetPayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len([]rune(payload.Name)) > TaskNameMaxLength {
+		return fmt.Errorf("name must be at most %d characters", TaskNameMaxLength)
+	}
+	if payload.EstimateMinutes <= 0 {
+		return fmt.Errorf("estimateMinutes must be positive")
+	}
+	if payload.EstimateMinutes > TaskEstimateMaxMinutes {
+		return fmt.Errorf("estimateMinutes must be at most %d", TaskEstimateMaxMinutes)
+	}
+	return nil
+}
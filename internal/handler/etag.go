@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag exposes the row version of a resource as a strong entity tag.
+func setETag(c *gin.Context, version int32) {
+	c.Header("ETag", `"`+strconv.Itoa(int(version))+`"`)
+}
+
+// parseIfMatch returns the versions listed in the If-Match header, or nil when
+// the header is absent or "*". Weak and foreign tags never match, so a header
+// made only of those yields an empty, non-nil slice.
+func parseIfMatch(c *gin.Context) []int32 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int32{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, int32(version))
+	}
+
+	return versions
+}
@@ -0,0 +1,394 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/holidays"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	MaxHolidayFileSize       = 1 << 20
+	HolidayCalendarNameLimit = 50
+)
+
+// @Summary      Import a holiday calendar
+// @Description  Create a holiday calendar from an ICS or JSON file, or replace the holidays of the calendar with the same name. JSON files hold an array of {"date": "YYYY-MM-DD", "name": "..."} objects.
+// @Tags         holidays
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file    formData  file                          true   "ICS or JSON file"
+// @Param        name    formData  string                        false  "Calendar name, defaults to the file name"
+// @Param        region  formData  string                        false  "Country or region the calendar applies to"
+// @Success      201     {object}  models.HolidayCalendarImport  "Calendar imported successfully"
+// @Failure      400     {object}  errorResponse                 "Bad request"
+// @Failure      403     {object}  errorResponse                 "Forbidden"
+// @Failure      500     {object}  errorResponse                 "Internal server error"
+// @Security     BearerAuth
+// @Router       /holiday-calendars [post]
+func (h *Handler) ImportHolidayCalendar(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logrus.Errorf("Missing holiday file: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if fileHeader.Size > MaxHolidayFileSize {
+		logrus.Errorf("Holiday file too large: %d bytes", fileHeader.Size)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logrus.Errorf("Error opening holiday file: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logrus.Errorf("Error reading holiday file: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	days, err := holidays.Parse(fileHeader.Filename, data)
+	if err != nil {
+		logrus.Errorf("Invalid holiday file: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
+	}
+	if name == "" || len(name) > HolidayCalendarNameLimit {
+		logrus.Errorf("Invalid holiday calendar name: %q", name)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	var region *string
+	if value := c.PostForm("region"); value != "" {
+		region = &value
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.service.IHolidayService.ImportHolidayCalendar(ctx, name, region, days)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		logrus.Errorf("Error importing holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Holiday calendar %q imported with %d holidays", name, result.Imported)
+	c.JSON(http.StatusCreated, result)
+}
+
+// @Summary      Get holiday calendars
+// @Description  Retrieve every imported holiday calendar
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.HolidayCalendar  "Calendars retrieved successfully"
+// @Failure      500  {object}  errorResponse           "Internal server error"
+// @Security     BearerAuth
+// @Router       /holiday-calendars [get]
+func (h *Handler) GetHolidayCalendars(c *gin.Context) {
+	ctx := c.Request.Context()
+	calendars, err := h.service.IHolidayService.GetHolidayCalendars(ctx)
+	if err != nil {
+		logrus.Errorf("Error retrieving holiday calendars: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, calendars)
+}
+
+// @Summary      Get holidays
+// @Description  Retrieve the holidays of a calendar in a year
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        calendarId  path      string          true   "Calendar id"
+// @Param        year        query     int             false  "Year, defaults to the current year"
+// @Success      200         {array}   models.Holiday  "Holidays retrieved successfully"
+// @Failure      400         {object}  errorResponse   "Bad request"
+// @Failure      404         {object}  errorResponse   "Holiday calendar not found"
+// @Failure      500         {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /holiday-calendars/{calendarId}/holidays [get]
+func (h *Handler) GetHolidays(c *gin.Context) {
+	calendarUUID, err := uuid.Parse(c.Param("calendarId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	year, err := parseYear(c)
+	if err != nil {
+		logrus.Errorf("Invalid year: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.service.IHolidayService.GetHolidays(ctx, calendarUUID, year)
+	if err != nil {
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar found for UUID: %s", calendarUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error retrieving holidays: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Delete a holiday calendar
+// @Description  Delete a holiday calendar and unassign it from its users
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        calendarId  path      string          true  "Calendar id"
+// @Success      200         {object}  statusResponse  "Holiday calendar deleted successfully"
+// @Failure      400         {object}  errorResponse   "Bad request"
+// @Failure      403         {object}  errorResponse   "Forbidden"
+// @Failure      404         {object}  errorResponse   "Holiday calendar not found"
+// @Failure      500         {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /holiday-calendars/{calendarId} [delete]
+func (h *Handler) DeleteHolidayCalendar(c *gin.Context) {
+	calendarUUID, err := uuid.Parse(c.Param("calendarId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IHolidayService.DeleteHolidayCalendar(ctx, calendarUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar found for UUID: %s", calendarUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error deleting holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Holiday calendar deleted successfully: UUID=%s", calendarUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "Holiday calendar deleted successfully"})
+}
+
+// @Summary      Assign a holiday calendar
+// @Description  Set the holiday calendar whose days are skipped in the expected hours of a user
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "User id"
+// @Param        payload  body      models.AssignHolidayCalendarPayload  true  "Assign Holiday Calendar Payload"
+// @Success      200      {object}  statusResponse                       "Holiday calendar assigned successfully"
+// @Failure      400      {object}  errorResponse                        "Bad request"
+// @Failure      403      {object}  errorResponse                        "Forbidden"
+// @Failure      404      {object}  errorResponse                        "User or holiday calendar not found"
+// @Failure      500      {object}  errorResponse                        "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/holiday-calendar [put]
+func (h *Handler) SetUserHolidayCalendar(c *gin.Context) {
+	var payload models.AssignHolidayCalendarPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IHolidayService.SetUserHolidayCalendar(ctx, userUUID, payload.CalendarUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar found for UUID: %s", payload.CalendarUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error assigning holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Description: "Holiday calendar assigned successfully"})
+}
+
+// @Summary      Unassign a holiday calendar
+// @Description  Remove the holiday calendar of a user
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string          true  "User id"
+// @Success      200  {object}  statusResponse  "Holiday calendar unassigned successfully"
+// @Failure      400  {object}  errorResponse   "Bad request"
+// @Failure      403  {object}  errorResponse   "Forbidden"
+// @Failure      404  {object}  errorResponse   "Holiday calendar not found"
+// @Failure      500  {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/holiday-calendar [delete]
+func (h *Handler) DeleteUserHolidayCalendar(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IHolidayService.DeleteUserHolidayCalendar(ctx, userUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar assigned to user UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error unassigning holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Description: "Holiday calendar unassigned successfully"})
+}
+
+// @Summary      Assign a team holiday calendar
+// @Description  Set the holiday calendar of the team of a manager, used for the members without a calendar of their own. Allowed to that manager and admins.
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Manager user id"
+// @Param        payload  body      models.AssignHolidayCalendarPayload  true  "Assign Holiday Calendar Payload"
+// @Success      200      {object}  statusResponse                       "Holiday calendar assigned successfully"
+// @Failure      400      {object}  errorResponse                        "Bad request"
+// @Failure      403      {object}  errorResponse                        "Forbidden"
+// @Failure      404      {object}  errorResponse                        "User or holiday calendar not found"
+// @Failure      500      {object}  errorResponse                        "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/team/holiday-calendar [put]
+func (h *Handler) SetTeamHolidayCalendar(c *gin.Context) {
+	var payload models.AssignHolidayCalendarPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	managerUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IHolidayService.SetTeamHolidayCalendar(ctx, managerUUID, payload.CalendarUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", managerUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar found for UUID: %s", payload.CalendarUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error assigning team holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Description: "Holiday calendar assigned successfully"})
+}
+
+// @Summary      Unassign a team holiday calendar
+// @Description  Remove the holiday calendar of the team of a manager
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string          true  "Manager user id"
+// @Success      200  {object}  statusResponse  "Holiday calendar unassigned successfully"
+// @Failure      400  {object}  errorResponse   "Bad request"
+// @Failure      403  {object}  errorResponse   "Forbidden"
+// @Failure      404  {object}  errorResponse   "Holiday calendar not found"
+// @Failure      500  {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/team/holiday-calendar [delete]
+func (h *Handler) DeleteTeamHolidayCalendar(c *gin.Context) {
+	managerUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IHolidayService.DeleteTeamHolidayCalendar(ctx, managerUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrHolidayCalendarNotFound) {
+			logrus.Infof("No holiday calendar assigned to the team of user UUID: %s", managerUUID)
+			newErrorResponse(c, http.StatusNotFound, "Holiday calendar not found")
+			return
+		}
+		logrus.Errorf("Error unassigning team holiday calendar: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Description: "Holiday calendar unassigned successfully"})
+}
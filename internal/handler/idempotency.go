@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+	IdempotencyKeyMaxLength   = 255
+)
+
+// responseRecorder keeps a copy of the response body written by a handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the first response of a user request carrying an
+// Idempotency-Key header for every retry with the same key. Server errors are
+// not recorded, so such requests are handled again when retried.
+func (h *Handler) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > IdempotencyKeyMaxLength {
+			logrus.Errorf("Idempotency key too long: %d characters", len(key))
+			newErrorResponse(c, http.StatusBadRequest, "Bad request")
+			return
+		}
+
+		// The handler reports an invalid user id itself.
+		userUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logrus.Errorf("Error reading request body: %v", err)
+			newErrorResponse(c, http.StatusBadRequest, "Bad request")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		stored, err := h.service.IIdempotencyService.ReserveIdempotencyKey(ctx, userUUID, key, requestHash(c.Request, body))
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserNotFound):
+				c.Next()
+			case errors.Is(err, service.ErrForbidden):
+				newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+				logrus.Infof("Idempotency key %q is in progress", key)
+				newErrorResponse(c, http.StatusConflict, "Request with this idempotency key is in progress")
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				logrus.Infof("Idempotency key %q reused with a different request", key)
+				newErrorResponse(c, http.StatusUnprocessableEntity, "Idempotency key reused with a different request")
+			default:
+				logrus.Errorf("Error reserving idempotency key: %v", err)
+				newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+			}
+			return
+		}
+
+		if stored != nil {
+			logrus.Infof("Replaying response for idempotency key %q", key)
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(stored.StatusCode, gin.MIMEJSON+"; charset=utf-8", stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		saved := false
+		defer func() {
+			if saved {
+				return
+			}
+			// Runs on panics too, so the key is never left in progress.
+			if err := h.service.IIdempotencyService.ReleaseIdempotencyKey(context.WithoutCancel(ctx), userUUID, key); err != nil {
+				logrus.Errorf("Error releasing idempotency key: %v", err)
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		response := &models.IdempotentResponse{StatusCode: recorder.Status(), Body: recorder.body.Bytes()}
+		if err := h.service.IIdempotencyService.SaveIdempotentResponse(context.WithoutCancel(ctx), userUUID, key, response); err != nil {
+			logrus.Errorf("Error saving idempotent response: %v", err)
+			return
+		}
+		saved = true
+	}
+}
+
+// requestHash identifies a request by its method, path and body, so that a key
+// reused for a different request is detected.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
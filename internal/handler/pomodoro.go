@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary      Get pomodoro settings
+// @Description  Retrieve the pomodoro cycle configuration of a user
+// @Tags         pomodoro
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string                   true  "User id"
+// @Success      200  {object}  models.PomodoroSettings  "Settings retrieved successfully"
+// @Failure      400  {object}  errorResponse            "Bad request"
+// @Failure      403  {object}  errorResponse            "Forbidden"
+// @Failure      404  {object}  errorResponse            "User not found"
+// @Failure      500  {object}  errorResponse            "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/pomodoro [get]
+func (h *Handler) GetPomodoroSettings(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.service.IPomodoroService.GetPomodoroSettings(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error retrieving pomodoro settings: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary      Update pomodoro settings
+// @Description  Update the focus and break lengths of a user's pomodoro cycle
+// @Tags         pomodoro
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                true  "User id"
+// @Param        payload  body      models.UpdatePomodoroSettingsPayload  true  "Pomodoro Settings Payload"
+// @Success      200      {object}  models.PomodoroSettings               "Settings updated successfully"
+// @Failure      400      {object}  errorResponse                         "Bad request"
+// @Failure      403      {object}  errorResponse                         "Forbidden"
+// @Failure      404      {object}  errorResponse                         "User not found"
+// @Failure      500      {object}  errorResponse                         "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/pomodoro [patch]
+func (h *Handler) UpdatePomodoroSettings(c *gin.Context) {
+	var payload models.UpdatePomodoroSettingsPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err := validateUpdatePomodoroSettingsPayload(&payload); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.service.IPomodoroService.UpdatePomodoroSettings(ctx, userUUID, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error updating pomodoro settings: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Pomodoro settings updated successfully for user UUID: %s", userUUID)
+	c.JSON(http.StatusOK, settings)
+}
+
+func validateUpdatePomodoroSettingsPayload(payload *models.UpdatePomodoroSettingsPayload) error {
+	fields := map[string]*int{
+		"focusMinutes":          payload.FocusMinutes,
+		"shortBreakMinutes":     payload.ShortBreakMinutes,
+		"longBreakMinutes":      payload.LongBreakMinutes,
+		"cyclesBeforeLongBreak": payload.CyclesBeforeLongBreak,
+	}
+	for name, value := range fields {
+		if value != nil && *value <= 0 {
+			return fmt.Errorf("%s must be positive", name)
+		}
+	}
+	return nil
+}
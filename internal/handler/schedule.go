@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"time-tracker/internal/models"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const MaxReportDays = 366
+
+var validGroupBy = map[string]interface{}{
+	models.GroupByDay:   nil,
+	models.GroupByWeek:  nil,
+	models.GroupByMonth: nil,
+}
+
+// @Summary      Set a work schedule
+// @Description  Create or replace the contracted hours per weekday of a user, effective from the given date
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                      true  "User id"
+// @Param        payload  body      models.WorkSchedulePayload  true  "Work Schedule Payload"
+// @Success      201      {object}  models.WorkSchedule         "Schedule saved successfully"
+// @Failure      400      {object}  errorResponse               "Bad request"
+// @Failure      403      {object}  errorResponse               "Forbidden"
+// @Failure      404      {object}  errorResponse               "User not found"
+// @Failure      500      {object}  errorResponse               "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/schedules [post]
+func (h *Handler) SetWorkSchedule(c *gin.Context) {
+	var payload models.WorkSchedulePayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	effectiveFrom, err := validateWorkSchedulePayload(&payload)
+	if err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	schedule, err := h.service.IScheduleService.SetWorkSchedule(ctx, userUUID, effectiveFrom, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error setting work schedule: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Work schedule saved successfully: %v", schedule)
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// @Summary      Get work schedules
+// @Description  Retrieve every schedule version of a user ordered by the date it takes effect
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string               true  "User id"
+// @Success      200  {array}   models.WorkSchedule  "Schedules retrieved successfully"
+// @Failure      400  {object}  errorResponse        "Bad request"
+// @Failure      403  {object}  errorResponse        "Forbidden"
+// @Failure      404  {object}  errorResponse        "User not found"
+// @Failure      500  {object}  errorResponse        "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/schedules [get]
+func (h *Handler) GetWorkSchedules(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	schedules, err := h.service.IScheduleService.GetWorkSchedules(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error retrieving work schedules: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @Summary      Delete a work schedule
+// @Description  Delete a schedule version of a user by its id
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string          true  "User id"
+// @Param        scheduleId  path      string          true  "Schedule id"
+// @Success      200         {object}  statusResponse  "Schedule deleted successfully"
+// @Failure      400         {object}  errorResponse   "Bad request"
+// @Failure      403         {object}  errorResponse   "Forbidden"
+// @Failure      404         {object}  errorResponse   "Schedule not found"
+// @Failure      500         {object}  errorResponse   "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/schedules/{scheduleId} [delete]
+func (h *Handler) DeleteWorkSchedule(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	scheduleUUID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.IScheduleService.DeleteWorkSchedule(ctx, userUUID, scheduleUUID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrScheduleNotFound) {
+			logrus.Infof("No schedule %s found for user UUID: %s", scheduleUUID, userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Schedule not found")
+			return
+		}
+		logrus.Errorf("Error deleting work schedule: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("Work schedule deleted successfully: UUID=%s", scheduleUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "Schedule deleted successfully"})
+}
+
+// @Summary      Get overtime report
+// @Description  Compare expected and tracked time of a user per day, week or month
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true   "User id"
+// @Param        from     query     string                 false  "First day of the report (YYYY-MM-DD), defaults to the first day of the current month"
+// @Param        to       query     string                 false  "Last day of the report (YYYY-MM-DD), defaults to today"
+// @Param        groupBy  query     string                 false  "Grouping ('day', 'week', 'month')"  default(day)
+// @Success      200      {object}  models.OvertimeReport  "Report retrieved successfully"
+// @Failure      400      {object}  errorResponse          "Bad request"
+// @Failure      403      {object}  errorResponse          "Forbidden"
+// @Failure      404      {object}  errorResponse          "User not found"
+// @Failure      500      {object}  errorResponse          "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/overtime [get]
+func (h *Handler) GetOvertimeReport(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	groupBy := c.DefaultQuery("groupBy", models.GroupByDay)
+	if _, isValid := validGroupBy[groupBy]; !isValid {
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		logrus.Errorf("Invalid date range: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.service.IScheduleService.GetOvertimeReport(ctx, userUUID, from, to, groupBy)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error building overtime report for user UUID: %s: %v", userUUID, err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseDateRange reads the from and to query parameters, defaulting to the
+// current month up to today.
+func parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	from := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date: %w", err)
+		}
+		from = parsed
+	}
+
+	to := today
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date: %w", err)
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to is before from")
+	}
+	if to.Sub(from) > MaxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("range exceeds %d days", MaxReportDays)
+	}
+
+	return from, to, nil
+}
+
+func validateWorkSchedulePayload(payload *models.WorkSchedulePayload) (time.Time, error) {
+	effectiveFrom, err := time.Parse(time.DateOnly, payload.EffectiveFrom)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("effectiveFrom must be a YYYY-MM-DD date")
+	}
+
+	hours := []float64{
+		payload.Monday,
+		payload.Tuesday,
+		payload.Wednesday,
+		payload.Thursday,
+		payload.Friday,
+		payload.Saturday,
+		payload.Sunday,
+	}
+	for _, h := range hours {
+		if h < 0 || h > 24 {
+			return time.Time{}, fmt.Errorf("daily hours must be between 0 and 24")
+		}
+	}
+
+	return effectiveFrom, nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OIDCStateCookie     = "oidc_state"
+	OIDCStateCookiePath = "/api/auth/oidc"
+	OIDCStateMaxAge     = 600 // Seconds, as the state token itself
+)
+
+// @Summary      Sign in with the identity provider
+// @Description  Redirect the browser to the OpenID provider. The login state is kept in an HttpOnly cookie until the callback.
+// @Tags         auth
+// @Success      302  {string}  string         "Redirect to the identity provider"
+// @Failure      404  {object}  errorResponse  "Single sign-on is not configured"
+// @Failure      500  {object}  errorResponse  "Internal server error"
+// @Router       /auth/oidc/login [get]
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	login, err := h.service.ISSOService.BeginLogin(ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrSSODisabled) {
+			newErrorResponse(c, http.StatusNotFound, "Single sign-on is not configured")
+			return
+		}
+		logrus.Errorf("Error starting OIDC login: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	// Lax lets the cookie follow the top-level redirect back from the provider.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, login.StateToken, OIDCStateMaxAge, OIDCStateCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// @Summary      Complete an identity provider sign-in
+// @Description  Exchange the authorization code returned by the OpenID provider for a session token. The token is used as a bearer JWT. An identity signing in for the first time is linked to the user with the same verified email.
+// @Tags         auth
+// @Produce      json
+// @Param        code   query     string          true  "Authorization code"
+// @Param        state  query     string          true  "Login state"
+// @Success      200    {object}  models.Session  "Signed in successfully"
+// @Failure      400    {object}  errorResponse   "Bad request"
+// @Failure      401    {object}  errorResponse   "Sign-in failed"
+// @Failure      403    {object}  errorResponse   "No user is linked to this identity, or the user is deactivated"
+// @Failure      404    {object}  errorResponse   "Single sign-on is not configured"
+// @Failure      500    {object}  errorResponse   "Internal server error"
+// @Router       /auth/oidc/callback [get]
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		logrus.Infof("Identity provider returned an error: %s %s", providerError, c.Query("error_description"))
+		newErrorResponse(c, http.StatusUnauthorized, "Sign-in failed")
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	stateToken, err := c.Cookie(OIDCStateCookie)
+	if code == "" || state == "" || err != nil {
+		logrus.Errorf("Missing OIDC code, state or state cookie")
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	// The state can only be used once.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, "", -1, OIDCStateCookiePath, "", c.Request.TLS != nil, true)
+
+	ctx := c.Request.Context()
+	session, err := h.service.ISSOService.CompleteLogin(ctx, stateToken, state, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSSODisabled):
+			newErrorResponse(c, http.StatusNotFound, "Single sign-on is not configured")
+		case errors.Is(err, service.ErrSSOFailed):
+			newErrorResponse(c, http.StatusUnauthorized, "Sign-in failed")
+		case errors.Is(err, service.ErrIdentityNotLinked):
+			newErrorResponse(c, http.StatusForbidden, "No user is linked to this identity")
+		case errors.Is(err, service.ErrUserDeactivated):
+			newErrorResponse(c, http.StatusForbidden, "User is deactivated")
+		default:
+			logrus.Errorf("Error completing OIDC login: %v", err)
+			newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
@@ -3,8 +3,11 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"time-tracker/internal/models"
 	"time-tracker/internal/service"
 
@@ -13,6 +16,16 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	TaskNameMaxLength        = 50
+	TaskDescriptionMaxLength = 1000
+	TaskNotesMaxLength       = 2000
+	TaskEstimateMaxMinutes   = 60 * 24 * 365 // One year
+
+	SearchQueryMaxLength = 200
+	SearchMaxLimit       = 100
+)
+
 var validPeriods = map[string]interface{}{
 	"day":   nil,
 	"week":  nil,
@@ -21,16 +34,20 @@ var validPeriods = map[string]interface{}{
 }
 
 // @Summary      Start a time task
-// @Description  Create a new task for a user
+// @Description  Create a new task for a user. With mode "pomodoro" the task is a focus interval stopped automatically. An optional startTime backdates the start within the configured limit.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Param        id     path      string                        true  "User id"
 // @Param        payload  body      models.CreateTaskPayload      true  "Task Payload"
+// @Param        Idempotency-Key  header  string                  false  "Key replaying the first response to retries of the same request"
 // @Success      201      {object}  models.Task                   "Task created successfully"
 // @Failure      400      {object}  errorResponse                 "Bad request"
-// @Failure      409      {object}  errorResponse                 "Task with this user id already exists. Please complete the active task first."
+// @Failure      403      {object}  errorResponse                 "Forbidden"
+// @Failure      409      {object}  errorResponse                 "Task with this user id already exists, the user is absent today or a request with this idempotency key is in progress"
+// @Failure      422      {object}  errorResponse                 "Idempotency key reused with a different request"
 // @Failure      500      {object}  errorResponse                 "Internal server error"
+// @Security     BearerAuth
 // @Router       /users/{id}/tasks/start [post]
 func (h *Handler) StartTimeTask(c *gin.Context) {
 	var payload models.CreateTaskPayload
@@ -48,16 +65,38 @@ func (h *Handler) StartTimeTask(c *gin.Context) {
 		return
 	}
 
+	if err := validateCreateTaskPayload(&payload); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
 	ctx := c.Request.Context()
 	task, err := h.service.ITaskService.CreateTask(ctx, userUUID, &payload)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStartTime) {
+			logrus.Warnf("Invalid start time for user UUID %s: %v", userUUID, err)
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrForeignKeyViolation) {
 			logrus.Warnf("Error creating task: %v", err)
 			newErrorResponse(c, http.StatusBadRequest, "Bad request")
+			return
 		}
 		if errors.Is(err, service.ErrTaskAlreadyExists) {
 			logrus.Warnf("Task with user UUID %s already exists: %v", userUUID, err)
 			newErrorResponse(c, http.StatusConflict, "Task with this user UUID already exists. Please complete the active task first.")
+			return
+		}
+		if errors.Is(err, service.ErrUserAbsent) {
+			logrus.Warnf("User %s is absent today", userUUID)
+			newErrorResponse(c, http.StatusConflict, "User is absent today. Set overrideAbsence to start the timer anyway.")
+			return
 		}
 		logrus.Errorf("Error starting task: %v", err)
 		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
@@ -69,17 +108,31 @@ func (h *Handler) StartTimeTask(c *gin.Context) {
 }
 
 // @Summary      Stop a time task
-// @Description  Stop an active task for a user
+// @Description  Stop an active task for a user, optionally appending notes to the recorded entry. An optional endTime stops the task at an earlier moment within the configured limit.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Param        id     path      string                        true  "User id"
+// @Param        payload  body      models.StopTaskPayload        false  "Stop Task Payload"
+// @Param        Idempotency-Key  header  string                  false  "Key replaying the first response to retries of the same request"
 // @Success      200      {object}  models.Task                   "Task stopped successfully"
 // @Failure      400      {object}  errorResponse                 "Bad request"
+// @Failure      403      {object}  errorResponse                 "Forbidden"
 // @Failure      404      {object}  errorResponse                 "No users found or this user does not have an active task yet."
+// @Failure      409      {object}  errorResponse                 "Request with this idempotency key is in progress"
+// @Failure      422      {object}  errorResponse                 "Idempotency key reused with a different request"
 // @Failure      500      {object}  errorResponse                 "Internal server error"
+// @Security BearerAuth
 // @Router /users/{id}/tasks/stop [post]
 func (h *Handler) StopTimeTask(c *gin.Context) {
+	// The body is optional, stopping without notes keeps working as before.
+	var payload models.StopTaskPayload
+	if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
 	userIDParam := c.Param("id")
 	userUUID, err := uuid.Parse(userIDParam)
 	if err != nil {
@@ -88,9 +141,24 @@ func (h *Handler) StopTimeTask(c *gin.Context) {
 		return
 	}
 
+	if payload.Notes != nil && len([]rune(*payload.Notes)) > TaskNotesMaxLength {
+		logrus.Errorf("Notes exceed %d characters", TaskNotesMaxLength)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
 	ctx := c.Request.Context()
-	task, err := h.service.ITaskService.FinishTask(ctx, userUUID)
+	task, err := h.service.ITaskService.FinishTask(ctx, userUUID, &payload)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidEndTime) {
+			logrus.Warnf("Invalid end time for user UUID %s: %v", userUUID, err)
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrUserNotFound) {
 			logrus.Infof("No user found for UUID: %s", userUUID)
 			newErrorResponse(c, http.StatusNotFound, "No users found")
@@ -110,6 +178,154 @@ func (h *Handler) StopTimeTask(c *gin.Context) {
 	c.JSON(http.StatusOK, task)
 }
 
+// @Summary      Switch a time task
+// @Description  Stop the active task of a user and start a new one in a single transaction. The new task starts exactly when the previous one ends, at start.startTime when given.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "User id"
+// @Param        payload  body      models.SwitchTaskPayload  true  "Switch Task Payload"
+// @Param        Idempotency-Key  header  string              false  "Key replaying the first response to retries of the same request"
+// @Success      201      {object}  models.SwitchedTask       "Task switched successfully"
+// @Failure      400      {object}  errorResponse             "Bad request"
+// @Failure      403      {object}  errorResponse             "Forbidden"
+// @Failure      404      {object}  errorResponse             "No users found or this user does not have an active task yet."
+// @Failure      409      {object}  errorResponse             "The user is absent today or a request with this idempotency key is in progress"
+// @Failure      422      {object}  errorResponse             "Idempotency key reused with a different request"
+// @Failure      500      {object}  errorResponse             "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/tasks/switch [post]
+func (h *Handler) SwitchTimeTask(c *gin.Context) {
+	var payload models.SwitchTaskPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err := validateCreateTaskPayload(&payload.Start); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if payload.StopNotes != nil && len([]rune(*payload.StopNotes)) > TaskNotesMaxLength {
+		logrus.Errorf("Notes exceed %d characters", TaskNotesMaxLength)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	switched, err := h.service.ITaskService.SwitchTask(ctx, userUUID, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStartTime) || errors.Is(err, service.ErrInvalidEndTime) {
+			logrus.Warnf("Invalid switch time for user UUID %s: %v", userUUID, err)
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskNotFound) {
+			logrus.Warnf("No active task for user UUID %s: %v", userUUID, err)
+			newErrorResponse(c, http.StatusNotFound, "This user does not have an active task yet.")
+			return
+		}
+		if errors.Is(err, service.ErrUserAbsent) {
+			logrus.Warnf("User %s is absent today", userUUID)
+			newErrorResponse(c, http.StatusConflict, "User is absent today. Set overrideAbsence to start the timer anyway.")
+			return
+		}
+		logrus.Errorf("Error switching task: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	logrus.Infof("Task switched successfully for user UUID: %s", userUUID)
+	c.JSON(http.StatusCreated, switched)
+}
+
+// @Summary      Continue a time task
+// @Description  Start a new task with the name, description and notes of the latest recorded task, or of the given entry. When the latest entry ended within the configured gap it is reopened with its original start instead.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                      true   "User id"
+// @Param        payload  body      models.ContinueTaskPayload  false  "Continue Task Payload"
+// @Param        Idempotency-Key  header  string                false  "Key replaying the first response to retries of the same request"
+// @Success      201      {object}  models.ContinuedTask        "Task continued successfully"
+// @Failure      400      {object}  errorResponse               "Bad request"
+// @Failure      403      {object}  errorResponse               "Forbidden"
+// @Failure      404      {object}  errorResponse               "User or task history entry not found"
+// @Failure      409      {object}  errorResponse               "Task with this user id already exists, the user is absent today or a request with this idempotency key is in progress"
+// @Failure      422      {object}  errorResponse               "Idempotency key reused with a different request"
+// @Failure      500      {object}  errorResponse               "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/tasks/continue [post]
+func (h *Handler) ContinueTimeTask(c *gin.Context) {
+	var payload models.ContinueTaskPayload
+	if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	continued, err := h.service.ITaskService.ContinueTask(ctx, userUUID, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskEntryNotFound) {
+			logrus.Infof("No task history entry to continue for user UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Task history entry not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAlreadyExists) {
+			logrus.Warnf("Task with user UUID %s already exists: %v", userUUID, err)
+			newErrorResponse(c, http.StatusConflict, "Task with this user UUID already exists. Please complete the active task first.")
+			return
+		}
+		if errors.Is(err, service.ErrUserAbsent) {
+			logrus.Warnf("User %s is absent today", userUUID)
+			newErrorResponse(c, http.StatusConflict, "User is absent today. Set overrideAbsence to start the timer anyway.")
+			return
+		}
+		logrus.Errorf("Error continuing task: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	logrus.Infof("Task continued successfully for user UUID: %s, reopened: %t", userUUID, continued.Reopened)
+	c.JSON(http.StatusCreated, continued)
+}
+
 // @Summary Get tasks result
 // @Description Retrieve tasks result for a user within a specified time period
 // @Tags tasks
@@ -121,8 +337,10 @@ func (h *Handler) StopTimeTask(c *gin.Context) {
 // @Success 200 {object} models.TasksResult "Tasks retrieved successfully"
 // @Success 204 {object} nil "No tasks found for the specified period"
 // @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 404 {object} errorResponse "User not found"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users/{id}/tasks/result [get]
 func (h *Handler) GetTasksResult(c *gin.Context) {
 	userIDParam := c.Param("id")
@@ -149,6 +367,10 @@ func (h *Handler) GetTasksResult(c *gin.Context) {
 	ctx := c.Request.Context()
 	task, err := h.service.ITaskService.GetTasksResult(ctx, userUUID, days)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
 		if errors.Is(err, service.ErrUserNotFound) {
 			logrus.Infof("No user found for UUID: %s", userUUID)
 			newErrorResponse(c, http.StatusNotFound, "User not found")
@@ -167,6 +389,326 @@ func (h *Handler) GetTasksResult(c *gin.Context) {
 	c.JSON(http.StatusOK, task)
 }
 
+// @Summary Search tasks
+// @Description Full-text search over the names, descriptions and notes of a user's recorded tasks, ranked by relevance. Mixed Russian and English text is supported. With team=true, the tasks of the users managed by the user are searched too, which is allowed to that manager and admins.
+// @Tags tasks
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Param q query string true "Search query, supports quoted phrases, OR and -exclusions"
+// @Param team query bool false "Also search the tasks of the team of the user" default(false)
+// @Param from query string false "First day of the search range (YYYY-MM-DD)"
+// @Param to query string false "Last day of the search range (YYYY-MM-DD)"
+// @Param limit query int false "Limit the number of results returned" default(20)
+// @Param offset query int false "Offset the number of results returned" default(0)
+// @Success 200 {array} models.TaskSearchResult "Search completed successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "User not found"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/tasks/search [get]
+func (h *Handler) SearchTasks(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len([]rune(query)) > SearchQueryMaxLength {
+		logrus.Errorf("Invalid search query: %q", query)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > SearchMaxLimit {
+		logrus.Errorf("Invalid limit parameter: %s", c.Query("limit"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		logrus.Errorf("Invalid offset parameter: %s", c.Query("offset"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	team, err := strconv.ParseBool(c.DefaultQuery("team", "false"))
+	if err != nil {
+		logrus.Errorf("Invalid team parameter: %s", c.Query("team"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	var from, to time.Time
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			logrus.Errorf("Invalid from date: %v", err)
+			newErrorResponse(c, http.StatusBadRequest, "Bad request")
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			logrus.Errorf("Invalid to date: %v", err)
+			newErrorResponse(c, http.StatusBadRequest, "Bad request")
+			return
+		}
+		// The last day is inclusive.
+		to = to.AddDate(0, 0, 1)
+	}
+
+	ctx := c.Request.Context()
+	results, err := h.service.ITaskService.SearchTasks(ctx, userUUID, query, team, from, to, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error searching tasks for user UUID: %s: %v", userUUID, err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary Suggest task names
+// @Description Suggest names for a new task from the user's own history, ranked by prefix match, similarity, frequency and recency. With team=true, the names used by the users managed by the user count too, which is allowed to that manager and admins.
+// @Tags tasks
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Param q query string false "Typed part of the task name"
+// @Param team query bool false "Rank the names by their use across the team of the user" default(false)
+// @Param limit query int false "Limit the number of suggestions returned" default(10)
+// @Success 200 {array} models.TaskNameSuggestion "Suggestions retrieved successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "User not found"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/tasks/suggestions [get]
+func (h *Handler) SuggestTaskNames(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if len([]rune(query)) > TaskNameMaxLength {
+		logrus.Errorf("Invalid suggestion query: %q", query)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > SearchMaxLimit {
+		logrus.Errorf("Invalid limit parameter: %s", c.Query("limit"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	team, err := strconv.ParseBool(c.DefaultQuery("team", "false"))
+	if err != nil {
+		logrus.Errorf("Invalid team parameter: %s", c.Query("team"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	suggestions, err := h.service.ITaskService.SuggestTaskNames(ctx, userUUID, query, team, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "User not found")
+			return
+		}
+		logrus.Errorf("Error suggesting task names for user UUID: %s: %v", userUUID, err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// @Summary      Get a task history entry
+// @Description  Retrieve a recorded task. The ETag header holds the entry version for conditional updates.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true  "User id"
+// @Param        entryId  path      string              true  "Task history entry id"
+// @Success      200      {object}  models.TaskHistory  "Task history entry retrieved successfully"
+// @Failure      400      {object}  errorResponse       "Bad request"
+// @Failure      403      {object}  errorResponse       "Forbidden"
+// @Failure      404      {object}  errorResponse       "Task history entry not found"
+// @Failure      500      {object}  errorResponse       "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/tasks/history/{entryId} [get]
+func (h *Handler) GetTaskHistoryEntry(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	entryUUID, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	entry, err := h.service.ITaskService.GetTaskHistoryEntry(ctx, userUUID, entryUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrTaskEntryNotFound) {
+			logrus.Infof("No task history entry %s for user UUID: %s", entryUUID, userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Task history entry not found")
+			return
+		}
+		logrus.Errorf("Error retrieving task history entry: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary      Update a task history entry
+// @Description  Edit the name, description or notes of a recorded task. With If-Match the edit only applies when the entry still has one of the given ETags.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                           true   "User id"
+// @Param        entryId   path      string                           true   "Task history entry id"
+// @Param        If-Match  header    string                           false  "ETag of the entry version the edit is based on"
+// @Param        payload   body      models.UpdateTaskHistoryPayload  true   "Update Task History Payload"
+// @Success      200       {object}  models.TaskHistory               "Task history entry updated successfully"
+// @Failure      400       {object}  errorResponse                    "Bad request"
+// @Failure      403       {object}  errorResponse                    "Forbidden"
+// @Failure      404       {object}  errorResponse                    "Task history entry not found"
+// @Failure      412       {object}  errorResponse                    "Task history entry was modified since it was retrieved"
+// @Failure      500       {object}  errorResponse                    "Internal server error"
+// @Security     BearerAuth
+// @Router       /users/{id}/tasks/history/{entryId} [patch]
+func (h *Handler) UpdateTaskHistoryEntry(c *gin.Context) {
+	var payload models.UpdateTaskHistoryPayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err := validateUpdateTaskHistoryPayload(&payload); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	entryUUID, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	entry, err := h.service.ITaskService.UpdateTaskHistoryEntry(ctx, userUUID, entryUUID, &payload, parseIfMatch(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrTaskEntryNotFound) {
+			logrus.Infof("No task history entry %s for user UUID: %s", entryUUID, userUUID)
+			newErrorResponse(c, http.StatusNotFound, "Task history entry not found")
+			return
+		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			logrus.Infof("Task history entry %s modified since retrieved", entryUUID)
+			newErrorResponse(c, http.StatusPreconditionFailed, "Task history entry was modified since it was retrieved")
+			return
+		}
+		logrus.Errorf("Error updating task history entry: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	logrus.Infof("Task history entry %s updated for user UUID: %s", entryUUID, userUUID)
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+func validateCreateTaskPayload(payload *models.CreateTaskPayload) error {
+	if payload.Name == "" || len([]rune(payload.Name)) > TaskNameMaxLength {
+		return fmt.Errorf("name must be between 1 and %d characters", TaskNameMaxLength)
+	}
+
+	if payload.Mode != "" && payload.Mode != models.TaskModeRegular && payload.Mode != models.TaskModePomodoro {
+		return fmt.Errorf("invalid task mode %q", payload.Mode)
+	}
+
+	if payload.Description != nil && len([]rune(*payload.Description)) > TaskDescriptionMaxLength {
+		return fmt.Errorf("description exceeds %d characters", TaskDescriptionMaxLength)
+	}
+
+	if payload.Notes != nil && len([]rune(*payload.Notes)) > TaskNotesMaxLength {
+		return fmt.Errorf("notes exceed %d characters", TaskNotesMaxLength)
+	}
+
+	if payload.EstimateMinutes != nil && (*payload.EstimateMinutes <= 0 || *payload.EstimateMinutes > TaskEstimateMaxMinutes) {
+		return fmt.Errorf("invalid estimateMinutes %d", *payload.EstimateMinutes)
+	}
+
+	return nil
+}
+
+func validateUpdateTaskHistoryPayload(payload *models.UpdateTaskHistoryPayload) error {
+	if payload.Name != nil && (*payload.Name == "" || len([]rune(*payload.Name)) > TaskNameMaxLength) {
+		return fmt.Errorf("name must be between 1 and %d characters", TaskNameMaxLength)
+	}
+
+	if payload.Description != nil && len([]rune(*payload.Description)) > TaskDescriptionMaxLength {
+		return fmt.Errorf("description exceeds %d characters", TaskDescriptionMaxLength)
+	}
+
+	if payload.Notes != nil && len([]rune(*payload.Notes)) > TaskNotesMaxLength {
+		return fmt.Errorf("notes exceed %d characters", TaskNotesMaxLength)
+	}
+
+	return nil
+}
+
 func convertPeriodToDays(period string, amount string) (int, error) {
 	amountInt, err := strconv.Atoi(amount)
 	if err != nil {
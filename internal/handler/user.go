@@ -1,10 +1,11 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/mail"
 	"regexp"
 	"strconv"
 	"time-tracker/internal/models"
@@ -22,14 +23,18 @@ var UserParamsArr = [5]string{"passport_number", "name", "surname", "patronymic"
 
 // @Summary Create a new user
 // @Tags users
-// @Description Create a new user with the given payload.
+// @Description Create a new user with the given payload. Only the passport number is required: a missing surname, name or address is taken with the patronymic from the people info API.
 // @Accept  json
 // @Produce  json
 // @Param payload body models.CreateUserPayload true "User creation payload"
 // @Success 201 {object} models.User "User created successfully"
 // @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 409 {object} errorResponse "User already exists"
+// @Failure 422 {object} errorResponse "No person is registered for the passport number"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Failure 503 {object} errorResponse "People info API is unavailable"
+// @Security BearerAuth
 // @Router /users [post]
 func (h *Handler) CreateUser(c *gin.Context) {
 	var payload models.CreateUserPayload
@@ -48,31 +53,51 @@ func (h *Handler) CreateUser(c *gin.Context) {
 	ctx := c.Request.Context()
 	user, err := h.service.IUserService.CreateUser(ctx, &payload)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
 		logrus.Errorf("Error creating user: %v", err)
 		if errors.Is(err, service.ErrUserAlreadyExists) {
 			newErrorResponse(c, http.StatusConflict, "User already exists")
 			return
 		}
+		if errors.Is(err, service.ErrPeopleInfoDisabled) {
+			newErrorResponse(c, http.StatusBadRequest, "Surname, name and address are required")
+			return
+		}
+		if errors.Is(err, service.ErrPersonNotFound) {
+			newErrorResponse(c, http.StatusUnprocessableEntity, "No person is registered for the passport number")
+			return
+		}
+		if errors.Is(err, service.ErrPeopleInfoFailed) {
+			newErrorResponse(c, http.StatusServiceUnavailable, "People info API is unavailable")
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	logrus.Infof("User created successfully: %v", user)
+	logrus.Infof("User created successfully: UUID=%s", user.UUID)
+	setETag(c, user.Version)
 	c.JSON(http.StatusCreated, user)
 }
 
 // @Summary Get all users
 // @Tags users
-// @Description Retrieve a list of users with optional filters, limit, and offset.
+// @Description Retrieve a list of users with optional filters, limit, and offset. Deactivated users are only listed with includeDeleted.
 // @Accept  json
 // @Produce  json
 // @Param limit query int false "Limit the number of users returned" default(10)
 // @Param offset query int false "Offset the number of users returned" default(0)
 // @Param filters query string false "Optional filters to apply on users"
+// @Param includeDeleted query bool false "Also list deactivated users" default(false)
 // @Success 200 {array}  models.User "List of users"
 // @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 404 {object} errorResponse "No users found"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users [get]
 func (h *Handler) GetAllUsers(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -96,9 +121,20 @@ func (h *Handler) GetAllUsers(c *gin.Context) {
 		}
 	}
 
+	includeDeleted, err := strconv.ParseBool(c.DefaultQuery("includeDeleted", "false"))
+	if err != nil {
+		logrus.Errorf("Invalid includeDeleted parameter: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
 	ctx := c.Request.Context()
-	users, err := h.service.IUserService.GetUsers(ctx, limit, offset, filters)
+	users, err := h.service.IUserService.GetUsers(ctx, limit, offset, filters, includeDeleted)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
 		if errors.Is(err, service.ErrUsersNotFound) {
 			logrus.Infof("No users found with filters: %v", filters)
 			newErrorResponse(c, http.StatusNotFound, "No users found")
@@ -124,6 +160,7 @@ func (h *Handler) GetAllUsers(c *gin.Context) {
 // @Failure 400 {object} errorResponse "Bad request"
 // @Failure 404 {object} errorResponse "User not found"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users/info [get]
 func (h *Handler) GetUserByPassportNumber(c *gin.Context) {
 	passportSerieParam := c.Query("passportSerie")
@@ -156,19 +193,22 @@ func (h *Handler) GetUserByPassportNumber(c *gin.Context) {
 	}
 
 	logrus.Infof("User retrieved successfully for passport number: %s", passportNumber)
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
 // @Summary Get user by id
 // @Tags users
-// @Description Retrieve a user by their id.
+// @Description Retrieve a user by their id. The ETag header holds the user version for conditional updates.
 // @Accept  json
 // @Produce  json
 // @Param id path string true "User id"
 // @Success 200 {object} models.User "User retrieved successfully"
 // @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 404 {object} errorResponse "No users found"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users/{id} [get]
 func (h *Handler) GetUser(c *gin.Context) {
 	userIDParam := c.Param("id")
@@ -182,6 +222,10 @@ func (h *Handler) GetUser(c *gin.Context) {
 	ctx := c.Request.Context()
 	user, err := h.service.IUserService.GetUserByUUID(ctx, userUUID)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
 		if errors.Is(err, service.ErrUserNotFound) {
 			logrus.Infof("No user found for UUID: %s", userUUID)
 			newErrorResponse(c, http.StatusNotFound, "No users found")
@@ -193,30 +237,48 @@ func (h *Handler) GetUser(c *gin.Context) {
 	}
 
 	logrus.Infof("User retrieved successfully: %v", user)
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
 // @Summary Update user by id
 // @Tags users
-// @Description Update a user's details by their id.
-// @Accept  json
+// @Description Update a user's details by their id. Only admins may update users. The patch is a JSON Merge Patch (RFC 7396, application/merge-patch+json or application/json) or a JSON Patch (RFC 6902, application/json-patch+json). In a merge patch an absent member is left unchanged and null clears patronymic or email; the other members cannot be null. With If-Match the update only applies when the user still has one of the given ETags.
+// @Accept  json,application/merge-patch+json,application/json-patch+json
 // @Produce  json
 // @Param id path string true "User id"
-// @Param payload body models.UpdateUserPayload true "User Update Payload"
+// @Param If-Match header string false "ETag of the user version the update is based on"
+// @Param payload body models.UpdateUserPayload true "User merge patch, or an array of JSON Patch operations"
 // @Success 200 {object} models.User "User updated successfully"
-// @Success 200 {object} statusResponse "No fields to update"
-// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 400 {object} errorResponse "Bad request or invalid patch"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 404 {object} errorResponse "No users found"
-// @Failure 409 {object} errorResponse "User with this passportNumber already exists"
+// @Failure 409 {object} errorResponse "User with this passportNumber already exists or a JSON Patch test failed"
+// @Failure 412 {object} errorResponse "User was modified since it was retrieved"
+// @Failure 415 {object} errorResponse "Unsupported patch format"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users/{id} [patch]
 func (h *Handler) UpdateUser(c *gin.Context) {
-	var payload models.UpdateUserPayload
-	if err := c.BindJSON(&payload); err != nil {
-		logrus.Errorf("Invalid JSON: %v", err)
+	var patch models.Patch
+	switch c.ContentType() {
+	case "", gin.MIMEJSON, models.PatchTypeMerge:
+		patch.Type = models.PatchTypeMerge
+	case models.PatchTypeJSON:
+		patch.Type = models.PatchTypeJSON
+	default:
+		logrus.Errorf("Unsupported patch content type: %q", c.ContentType())
+		newErrorResponse(c, http.StatusUnsupportedMediaType, "Unsupported patch format")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(body) == 0 {
+		logrus.Errorf("Missing patch body: %v", err)
 		newErrorResponse(c, http.StatusBadRequest, "Bad request")
 		return
 	}
+	patch.Body = body
 
 	userIDParam := c.Param("id")
 	userUUID, err := uuid.Parse(userIDParam)
@@ -226,49 +288,59 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	if payload.PassportNumber != nil ||
-		payload.Name != nil ||
-		payload.Surname != nil ||
-		payload.Patronymic != nil ||
-		payload.Address != nil {
-
-		ctx := c.Request.Context()
-		user, err := h.service.IUserService.UpdateUserByUUID(ctx, userUUID, &payload)
-		if err != nil {
-			logrus.Errorf("Error updating user: %v", err)
-			if errors.Is(err, service.ErrUserNotFound) {
-				logrus.Infof("No user found for UUID: %s", userUUID)
-				newErrorResponse(c, http.StatusNotFound, "No users found")
-				return
-			}
-			if errors.Is(err, service.ErrUserAlreadyExists) {
-				logrus.Warnf("User with passport number already exists: %v", err)
-				newErrorResponse(c, http.StatusConflict, "user with this passportNumber already exists")
-				return
-			}
-			newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+	ctx := c.Request.Context()
+	user, err := h.service.IUserService.UpdateUserByUUID(ctx, userUUID, &patch, parseIfMatch(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
 			return
 		}
-
-		logrus.Infof("User updated successfully: %v", user)
-		c.JSON(http.StatusOK, user)
+		logrus.Errorf("Error updating user: %v", err)
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPatch) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPatchTestFailed) {
+			newErrorResponse(c, http.StatusConflict, "JSON Patch test failed")
+			return
+		}
+		if errors.Is(err, service.ErrUserAlreadyExists) {
+			logrus.Warnf("User with passport number already exists: %v", err)
+			newErrorResponse(c, http.StatusConflict, "user with this passportNumber already exists")
+			return
+		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			newErrorResponse(c, http.StatusPreconditionFailed, "User was modified since it was retrieved")
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	logrus.Infof("No fields to update for UUID: %s", userUUID)
-	c.JSON(http.StatusOK, statusResponse{Description: "No fields to update"})
+	logrus.Infof("User updated successfully: %v", user)
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
 }
 
 // @Summary Delete user by id
 // @Tags users
-// @Description Delete a user by their id.
+// @Description Deactivate a user by their id. The user and their history are kept but hidden from the user list, and the user can no longer sign in or be changed until restored. With If-Match the user is only deactivated when it still has one of the given ETags.
 // @Accept  json
 // @Produce  json
 // @Param id path string true "User id"
+// @Param If-Match header string false "ETag of the user version the deletion is based on"
 // @Success 200 {object} statusResponse "User deleted successfully"
 // @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
 // @Failure 404 {object} errorResponse "No users found"
+// @Failure 412 {object} errorResponse "User was modified since it was retrieved"
 // @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /users/{id} [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
 	userIDParam := c.Param("id")
@@ -279,13 +351,23 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = h.service.IUserService.DeleteUserByUUID(context.Background(), userUUID)
+	ctx := c.Request.Context()
+	err = h.service.IUserService.DeleteUserByUUID(ctx, userUUID, parseIfMatch(c))
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
 		if errors.Is(err, service.ErrUserNotFound) {
 			logrus.Infof("No user found for UUID: %s", userUUID)
 			newErrorResponse(c, http.StatusNotFound, "No users found")
 			return
 		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			logrus.Infof("User modified since retrieved: UUID=%s", userUUID)
+			newErrorResponse(c, http.StatusPreconditionFailed, "User was modified since it was retrieved")
+			return
+		}
 		logrus.Errorf("Error deleting user: %v", err)
 		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
 		return
@@ -295,18 +377,332 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, statusResponse{Description: "User deleted successfully"})
 }
 
+// @Summary Restore user by id
+// @Tags users
+// @Description Reactivate a deactivated user.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Success 200 {object} models.User "User restored successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "No users found"
+// @Failure 409 {object} errorResponse "Anonymized users cannot be restored"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/restore [post]
+func (h *Handler) RestoreUser(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.service.IUserService.RestoreUserByUUID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		if errors.Is(err, service.ErrUserAnonymized) {
+			newErrorResponse(c, http.StatusConflict, "Anonymized users cannot be restored")
+			return
+		}
+		logrus.Errorf("Error restoring user: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("User restored successfully: UUID=%s", userUUID)
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Purge user by id
+// @Tags users
+// @Description Permanently delete a deactivated user with their tracked time, settings and personal data. Only the audit log keeps a trace of the user. With If-Match the user is only purged when it still has one of the given ETags.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Param If-Match header string false "ETag of the user version the purge is based on"
+// @Success 200 {object} statusResponse "User purged successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "No users found"
+// @Failure 409 {object} errorResponse "User must be deactivated before it is purged"
+// @Failure 412 {object} errorResponse "User was modified since it was retrieved"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/purge [post]
+func (h *Handler) PurgeUser(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.service.IUserService.PurgeUserByUUID(ctx, userUUID, parseIfMatch(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		if errors.Is(err, service.ErrUserActive) {
+			newErrorResponse(c, http.StatusConflict, "User must be deactivated before it is purged")
+			return
+		}
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			logrus.Infof("User modified since retrieved: UUID=%s", userUUID)
+			newErrorResponse(c, http.StatusPreconditionFailed, "User was modified since it was retrieved")
+			return
+		}
+		logrus.Errorf("Error purging user: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("User purged successfully: UUID=%s", userUUID)
+	c.JSON(http.StatusOK, statusResponse{Description: "User purged successfully"})
+}
+
+// @Summary Export user data
+// @Tags users
+// @Description Download everything stored about a user as JSON: the profile, the tracked time, settings, absences, linked identities, API keys, and the audit and personal data access log entries about or by the user. Only admins may export user data.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Success 200 {object} models.UserExport "User data exported successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "No users found"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/export [get]
+func (h *Handler) ExportUserData(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	export, err := h.service.IUserService.ExportUserData(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		logrus.Errorf("Error exporting user data: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("User data exported successfully: UUID=%s", userUUID)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s.json"`, userUUID))
+	c.JSON(http.StatusOK, export)
+}
+
+// @Summary Anonymize user by id
+// @Tags users
+// @Description Permanently remove the name, surname, patronymic, address, passport number and email of a user, also from the audit log, and deactivate the user. The tracked time is kept for reports. Linked identities are unlinked and API keys revoked. Anonymized users cannot be restored.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Success 200 {object} models.User "User anonymized successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "No users found"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/anonymize [post]
+func (h *Handler) AnonymizeUser(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.service.IUserService.AnonymizeUserByUUID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		logrus.Errorf("Error anonymizing user: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("User anonymized successfully: UUID=%s", userUUID)
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Set user role
+// @Tags users
+// @Description Assign the role of a user, the manager whose team the user belongs to and the permissions granted on top of the role, such as pii:read. Only admins may change roles.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Param payload body models.SetUserRolePayload true "Set User Role Payload"
+// @Success 200 {object} models.User "User role set successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 404 {object} errorResponse "No users found"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/role [put]
+func (h *Handler) SetUserRole(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	var payload models.SetUserRolePayload
+	if err := c.BindJSON(&payload); err != nil {
+		logrus.Errorf("Invalid JSON: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err := validateSetUserRolePayload(&payload); err != nil {
+		logrus.Errorf("Validation error: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.service.IUserService.SetUserRole(ctx, userUUID, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			logrus.Infof("No user found for UUID: %s", userUUID)
+			newErrorResponse(c, http.StatusNotFound, "No users found")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidManager) {
+			logrus.Infof("Invalid manager for user UUID %s: %v", userUUID, payload.ManagerUUID)
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		logrus.Errorf("Error setting user role: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	logrus.Infof("User role set successfully: UUID=%s role=%s", userUUID, user.Role)
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Get PII accesses of a user
+// @Tags users
+// @Description List who read the personal data of a user in full, when and why, latest first. Only admins may read the log.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User id"
+// @Param limit query int false "Limit the number of accesses returned" default(50)
+// @Param offset query int false "Offset the number of accesses returned" default(0)
+// @Success 200 {array} models.PIIAccess "PII accesses retrieved successfully"
+// @Failure 400 {object} errorResponse "Bad request"
+// @Failure 403 {object} errorResponse "Forbidden"
+// @Failure 500 {object} errorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/pii-accesses [get]
+func (h *Handler) GetPIIAccesses(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		logrus.Errorf("Invalid UUID format: %v", err)
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		logrus.Errorf("Invalid limit parameter: %q", c.Query("limit"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		logrus.Errorf("Invalid offset parameter: %q", c.Query("offset"))
+		newErrorResponse(c, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	ctx := c.Request.Context()
+	accesses, err := h.service.IUserService.GetPIIAccesses(ctx, userUUID, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		logrus.Errorf("Error retrieving PII accesses: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, accesses)
+}
+
+func validateSetUserRolePayload(payload *models.SetUserRolePayload) error {
+	for _, permission := range payload.Permissions {
+		if permission != models.PermissionPIIRead {
+			return fmt.Errorf("unknown permission %q", permission)
+		}
+	}
+
+	switch payload.Role {
+	case models.RoleAdmin, models.RoleManager, models.RoleEmployee:
+		return nil
+	case "":
+		return fmt.Errorf("role is required")
+	default:
+		return fmt.Errorf("role must be one of %s, %s or %s", models.RoleAdmin, models.RoleManager, models.RoleEmployee)
+	}
+}
+
 func validateCreateUserPayload(payload *models.CreateUserPayload) error {
 	if payload.PassportNumber == "" {
 		return fmt.Errorf("passportNumber is required")
 	}
-	if payload.Name == "" {
-		return fmt.Errorf("name is required")
-	}
-	if payload.Surname == "" {
-		return fmt.Errorf("surname is required")
-	}
-	if payload.Address == "" {
-		return fmt.Errorf("address is required")
+	if payload.Email != nil {
+		if address, err := mail.ParseAddress(*payload.Email); err != nil || address.Address != *payload.Email {
+			return fmt.Errorf("invalid email address")
+		}
 	}
 	return validatePassportNumber(payload.PassportNumber)
 }
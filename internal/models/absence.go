@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	AbsenceTypeVacation  = "vacation"
+	AbsenceTypeSickLeave = "sick_leave"
+	AbsenceTypePersonal  = "personal"
+	AbsenceTypeUnpaid    = "unpaid"
+)
+
+type CreateAbsencePayload struct {
+	Type         string  `json:"type"`
+	StartDate    string  `json:"startDate"`
+	EndDate      string  `json:"endDate"`
+	HalfDayStart bool    `json:"halfDayStart"`
+	HalfDayEnd   bool    `json:"halfDayEnd"`
+	Note         *string `json:"note"`
+}
+
+type Absence struct {
+	UUID         uuid.UUID `json:"uuid"`
+	Type         string    `json:"type"`
+	StartDate    string    `json:"startDate"`
+	EndDate      string    `json:"endDate"`
+	HalfDayStart bool      `json:"halfDayStart"`
+	HalfDayEnd   bool      `json:"halfDayEnd"`
+	Note         *string   `json:"note,omitempty"`
+}
+
+type AbsenceBalancePayload struct {
+	Year          int     `json:"year"`
+	Type          string  `json:"type"`
+	AllowanceDays float64 `json:"allowanceDays"`
+}
+
+type AbsenceBalance struct {
+	Year          int     `json:"year"`
+	Type          string  `json:"type"`
+	AllowanceDays float64 `json:"allowanceDays"`
+	UsedDays      float64 `json:"usedDays"`
+	RemainingDays float64 `json:"remainingDays"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEntityUser        = "user"
+	AuditEntityTask        = "task"
+	AuditEntityTaskHistory = "task_history"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// AuditActionRestore, AuditActionPurge and AuditActionAnonymize are only
+	// recorded for users, whose deletion deactivates them.
+	AuditActionRestore   = "restore"
+	AuditActionPurge     = "purge"
+	AuditActionAnonymize = "anonymize"
+)
+
+// AuditEntry is a change to a user, a task or a history entry. Before and
+// After hold the members that changed, Before is missing for creations and
+// After for deletions.
+type AuditEntry struct {
+	UUID          uuid.UUID       `json:"uuid"`
+	Actor         string          `json:"actor"`
+	ActorUserUUID *uuid.UUID      `json:"actorUserUuid,omitempty"`
+	APIKeyUUID    *uuid.UUID      `json:"apiKeyUuid,omitempty"`
+	RequestID     *string         `json:"requestId,omitempty"`
+	SourceIP      *string         `json:"sourceIp,omitempty"`
+	Entity        string          `json:"entity"`
+	EntityUUID    uuid.UUID       `json:"entityUuid"`
+	UserUUID      uuid.UUID       `json:"userUuid"` // User the entity belongs to
+	Action        string          `json:"action"`
+	Before        json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After         json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// AuditFilter narrows the audit entries returned. Nil members match
+// everything.
+type AuditFilter struct {
+	Entity     *string
+	EntityUUID *uuid.UUID
+	UserUUID   *uuid.UUID
+	Actor      *string
+	Action     *string
+	From       *time.Time
+	To         *time.Time
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject          string     `json:"subject"`
+	Method           string     `json:"method"`
+	Role             string     `json:"role"`
+	Permissions      []string   `json:"permissions,omitempty"`
+	OrganizationUUID uuid.UUID  `json:"organizationUuid"`
+	UserUUID         *uuid.UUID `json:"userUuid,omitempty"`   // Linked user, when the subject is one
+	APIKeyUUID       *uuid.UUID `json:"apiKeyUuid,omitempty"` // Key used to authenticate, for API keys
+}
+
+type CreateAPIKeyPayload struct {
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type APIKey struct {
+	UUID       uuid.UUID  `json:"uuid"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+type CreatedAPIKey struct {
+	APIKey APIKey `json:"apiKey"`
+	Key    string `json:"key"` // Secret key, only returned once
+}
+
+// Identity is a user authenticated by an external identity provider.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// SSOLogin starts a sign-in with the identity provider. StateToken binds the
+// callback to the browser that started the login.
+type SSOLogin struct {
+	AuthURL    string
+	StateToken string
+}
+
+type Session struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"tokenType"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type HolidayCalendar struct {
+	UUID   uuid.UUID `json:"uuid"`
+	Name   string    `json:"name"`
+	Region *string   `json:"region,omitempty"`
+}
+
+type HolidayCalendarImport struct {
+	Calendar HolidayCalendar `json:"calendar"`
+	Imported int             `json:"imported"`
+}
+
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type AssignHolidayCalendarPayload struct {
+	CalendarUUID uuid.UUID `json:"calendarUuid"`
+}
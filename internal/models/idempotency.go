@@ -0,0 +1,7 @@
+package models
+
+// IdempotentResponse is the first response recorded for an idempotency key.
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
@@ -0,0 +1,12 @@
+package models
+
+const (
+	PatchTypeMerge = "application/merge-patch+json" // RFC 7396
+	PatchTypeJSON  = "application/json-patch+json"  // RFC 6902
+)
+
+// Patch is a change document in one of the PatchType formats.
+type Patch struct {
+	Type string
+	Body []byte
+}
@@ -0,0 +1,22 @@
+package models
+
+type PomodoroSettings struct {
+	FocusMinutes          int  `json:"focusMinutes"`
+	ShortBreakMinutes     int  `json:"shortBreakMinutes"`
+	LongBreakMinutes      int  `json:"longBreakMinutes"`
+	CyclesBeforeLongBreak int  `json:"cyclesBeforeLongBreak"`
+	AutoStartBreak        bool `json:"autoStartBreak"`
+}
+
+type UpdatePomodoroSettingsPayload struct {
+	FocusMinutes          *int  `json:"focusMinutes"`
+	ShortBreakMinutes     *int  `json:"shortBreakMinutes"`
+	LongBreakMinutes      *int  `json:"longBreakMinutes"`
+	CyclesBeforeLongBreak *int  `json:"cyclesBeforeLongBreak"`
+	AutoStartBreak        *bool `json:"autoStartBreak"`
+}
+
+type PomodoroDay struct {
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week"
+	GroupByMonth = "month"
+)
+
+type WorkSchedulePayload struct {
+	EffectiveFrom string  `json:"effectiveFrom"`
+	Monday        float64 `json:"monday"`
+	Tuesday       float64 `json:"tuesday"`
+	Wednesday     float64 `json:"wednesday"`
+	Thursday      float64 `json:"thursday"`
+	Friday        float64 `json:"friday"`
+	Saturday      float64 `json:"saturday"`
+	Sunday        float64 `json:"sunday"`
+}
+
+type WorkSchedule struct {
+	UUID          uuid.UUID `json:"uuid"`
+	EffectiveFrom string    `json:"effectiveFrom"`
+	Monday        float64   `json:"monday"`
+	Tuesday       float64   `json:"tuesday"`
+	Wednesday     float64   `json:"wednesday"`
+	Thursday      float64   `json:"thursday"`
+	Friday        float64   `json:"friday"`
+	Saturday      float64   `json:"saturday"`
+	Sunday        float64   `json:"sunday"`
+	WeeklyHours   float64   `json:"weeklyHours"`
+}
+
+type OvertimePeriod struct {
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+	Absent    string `json:"absent"`
+	Overtime  string `json:"overtime"`
+	Undertime string `json:"undertime"`
+}
+
+type OvertimeReport struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	GroupBy   string           `json:"groupBy"`
+	Expected  string           `json:"expected"`
+	Actual    string           `json:"actual"`
+	Absent    string           `json:"absent"`
+	Overtime  string           `json:"overtime"`
+	Undertime string           `json:"undertime"`
+	Periods   []OvertimePeriod `json:"periods"`
+}
@@ -6,14 +6,56 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	TaskModeRegular  = "regular"
+	TaskModeFocus    = "focus"
+	TaskModeBreak    = "break"
+	TaskModePomodoro = "pomodoro" // requested in CreateTaskPayload, stored as a focus interval
+)
+
 type CreateTaskPayload struct {
-	Name string `json:"name"`
+	Name            string     `json:"name"`
+	Description     *string    `json:"description,omitempty"`
+	Notes           *string    `json:"notes,omitempty"` // Initial notes, more can be appended when stopping
+	Mode            string     `json:"mode,omitempty"`
+	StartTime       *time.Time `json:"startTime,omitempty"` // Backdated start, defaults to now
+	EstimateMinutes *int       `json:"estimateMinutes,omitempty"`
+	OverrideAbsence bool       `json:"overrideAbsence,omitempty"`
+}
+
+type StopTaskPayload struct {
+	Notes   *string    `json:"notes,omitempty"`
+	EndTime *time.Time `json:"endTime,omitempty"` // Explicit end, defaults to now
 }
 
 type Task struct {
-	UUID      uuid.UUID  `json:"uuid"`
-	UserUUID  uuid.UUID  `json:"userUuid"`
-	Name      string     `json:"name"`
-	StartTime time.Time  `json:"startTime"`
-	EndTime   *time.Time `json:"endTime,omitempty"`
+	UUID           uuid.UUID  `json:"uuid"`
+	UserUUID       uuid.UUID  `json:"userUuid"`
+	Name           string     `json:"name"`
+	Description    *string    `json:"description,omitempty"`
+	Notes          *string    `json:"notes,omitempty"`
+	Mode           string     `json:"mode"`
+	StartTime      time.Time  `json:"startTime"`
+	EndTime        *time.Time `json:"endTime,omitempty"`
+	PlannedEndTime *time.Time `json:"plannedEndTime,omitempty"`
+}
+
+type SwitchTaskPayload struct {
+	Start     CreateTaskPayload `json:"start"`
+	StopNotes *string           `json:"stopNotes,omitempty"` // Notes appended to the stopped task
+}
+
+type SwitchedTask struct {
+	Stopped CompletedTask `json:"stopped"`
+	Started Task          `json:"started"`
+}
+
+type ContinueTaskPayload struct {
+	EntryUUID       *uuid.UUID `json:"entryUuid,omitempty"` // History entry to continue, defaults to the latest one
+	OverrideAbsence bool       `json:"overrideAbsence,omitempty"`
+}
+
+type ContinuedTask struct {
+	Task     Task `json:"task"`
+	Reopened bool `json:"reopened"` // The latest entry was reopened instead of starting a new one
 }
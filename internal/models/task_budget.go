@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskBudgetPayload struct {
+	Name            string `json:"name"`
+	EstimateMinutes int    `json:"estimateMinutes"`
+}
+
+type TaskBudget struct {
+	UUID            uuid.UUID `json:"uuid"`
+	Name            string    `json:"name"`
+	Estimate        string    `json:"estimate"`
+	Actual          string    `json:"actual"`
+	Remaining       string    `json:"remaining"`
+	ConsumedPercent int       `json:"consumedPercent"`
+}
+
+type BudgetEvent struct {
+	BudgetUUID      uuid.UUID `json:"budgetUuid"`
+	UserUUID        uuid.UUID `json:"userUuid"`
+	Name            string    `json:"name"`
+	Threshold       int       `json:"threshold"`
+	ConsumedPercent int       `json:"consumedPercent"`
+	Estimate        string    `json:"estimate"`
+	Actual          string    `json:"actual"`
+	OccurredAt      time.Time `json:"occurredAt"`
+}
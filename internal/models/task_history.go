@@ -7,20 +7,54 @@ import (
 )
 
 type TaskHistory struct {
-	Uuid      uuid.UUID `json:"uuid"`
-	TaskUuid  uuid.UUID `json:"taskUuid"`
-	UserUuid  uuid.UUID `json:"userUuid"`
-	Name      string    `json:"name"`
-	StartTime time.Time `json:"startTime"`
-	EndTime   time.Time `json:"endtime"`
+	Uuid        uuid.UUID `json:"uuid"`
+	TaskUuid    uuid.UUID `json:"taskUuid"`
+	UserUuid    uuid.UUID `json:"userUuid"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Notes       *string   `json:"notes,omitempty"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endtime"`
+	Mode        string    `json:"mode,omitempty"`
+	Version     int32     `json:"version"`
+}
+
+type UpdateTaskHistoryPayload struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Notes       *string `json:"notes"`
 }
 
 type CompletedTask struct {
-	Name     string `json:"name"`
-	Duration string `json:"duration"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+	Duration    string  `json:"duration"`
 }
 
 type TasksResult struct {
 	TotalDuration string          `json:"totalDuration"`
 	CompletedTask []CompletedTask `json:"CompletedTask"`
+	Budgets       []TaskBudget    `json:"budgets,omitempty"`
+	Pomodoros     []PomodoroDay   `json:"pomodoros,omitempty"`
+}
+
+type TaskSearchResult struct {
+	UUID          uuid.UUID `json:"uuid"`
+	UserUUID      uuid.UUID `json:"userUuid"`
+	Name          string    `json:"name"`
+	Description   *string   `json:"description,omitempty"`
+	Notes         *string   `json:"notes,omitempty"`
+	StartTime     time.Time `json:"startTime"`
+	EndTime       time.Time `json:"endTime"`
+	Duration      string    `json:"duration"`
+	Rank          float64   `json:"rank"`
+	NameHighlight string    `json:"nameHighlight"`           // Name with matches wrapped in <mark> tags
+	TextHighlight string    `json:"textHighlight,omitempty"` // Fragments of description and notes with matches wrapped in <mark> tags
+}
+
+type TaskNameSuggestion struct {
+	Name     string    `json:"name"`
+	Uses     int       `json:"uses"`
+	LastUsed time.Time `json:"lastUsed"`
 }
@@ -6,29 +6,108 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	RoleAdmin    = "admin"
+	RoleManager  = "manager"
+	RoleEmployee = "employee"
+)
+
+// PermissionPIIRead grants reading personal data in full, which is otherwise
+// reserved to admins.
+const PermissionPIIRead = "pii:read"
+
+// CreateUserPayload creates a user. Surname, name, patronymic and address
+// missing from the payload are taken from the people info API.
 type CreateUserPayload struct {
 	PassportNumber string  `json:"passportNumber"`
 	Surname        string  `json:"surname"`
 	Name           string  `json:"name"`
 	Patronymic     *string `json:"patronymic"`
 	Address        string  `json:"address"`
+	Email          *string `json:"email"`
 }
 
+// PersonInfo is the personal data registered for a passport in the people
+// info API.
+type PersonInfo struct {
+	Surname    string
+	Name       string
+	Patronymic *string
+	Address    string
+}
+
+// UpdateUserPayload documents the members of a user merge patch. An explicit
+// null clears patronymic or email; the other members cannot be null.
 type UpdateUserPayload struct {
 	PassportNumber *string `json:"passportNumber"`
 	Surname        *string `json:"surname"`
 	Name           *string `json:"name"`
 	Patronymic     *string `json:"patronymic"`
 	Address        *string `json:"address"`
+	Email          *string `json:"email"`
 }
 
 type User struct {
-	UUID           uuid.UUID `json:"uuid"`
-	PassportNumber string    `json:"passportNumber"`
-	Surname        string    `json:"surname"`
-	Name           string    `json:"name"`
-	Patronymic     *string   `json:"patronymic,omitempty"`
-	Address        string    `json:"address"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
+	UUID             uuid.UUID  `json:"uuid"`
+	PassportNumber   string     `json:"passportNumber"`
+	Surname          string     `json:"surname"`
+	Name             string     `json:"name"`
+	Patronymic       *string    `json:"patronymic,omitempty"`
+	Address          string     `json:"address"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	Version          int32      `json:"version"`
+	Role             string     `json:"role"`
+	ManagerUUID      *uuid.UUID `json:"managerUuid,omitempty"`
+	OrganizationUUID uuid.UUID  `json:"organizationUuid"`
+	Email            *string    `json:"email,omitempty"`
+	Permissions      []string   `json:"permissions"`
+	DeletedAt        *time.Time `json:"deletedAt,omitempty"` // Set while the user is deactivated
+	AnonymizedAt     *time.Time `json:"anonymizedAt,omitempty"`
+}
+
+// SetUserRolePayload assigns the role of a user, the manager whose team the
+// user belongs to and the permissions granted on top of the role.
+type SetUserRolePayload struct {
+	Role        string     `json:"role"`
+	ManagerUUID *uuid.UUID `json:"managerUuid"`
+	Permissions []string   `json:"permissions"`
+}
+
+// PIIAccess is a full read of the personal data of a user.
+type PIIAccess struct {
+	UUID          uuid.UUID  `json:"uuid"`
+	Actor         string     `json:"actor"`
+	ActorUserUUID *uuid.UUID `json:"actorUserUuid,omitempty"`
+	APIKeyUUID    *uuid.UUID `json:"apiKeyUuid,omitempty"`
+	UserUUID      uuid.UUID  `json:"userUuid"`
+	Field         string     `json:"field"`
+	Operation     string     `json:"operation"`
+	Reason        *string    `json:"reason,omitempty"`
+	AccessedAt    time.Time  `json:"accessedAt"`
+}
+
+// UserIdentity is an identity provider account linked to a user.
+type UserIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserExport is everything stored about a user.
+type UserExport struct {
+	ExportedAt          time.Time               `json:"exportedAt"`
+	Profile             User                    `json:"profile"`
+	ActiveTask          *Task                   `json:"activeTask,omitempty"`
+	History             []TaskHistory           `json:"history"`
+	PomodoroSettings    *PomodoroSettings       `json:"pomodoroSettings,omitempty"`
+	WorkSchedules       []WorkSchedule          `json:"workSchedules"`
+	Absences            []Absence               `json:"absences"`
+	AbsenceAllowances   []AbsenceBalancePayload `json:"absenceAllowances"`
+	TaskBudgets         []TaskBudget            `json:"taskBudgets"`
+	HolidayCalendarUUID *uuid.UUID              `json:"holidayCalendarUuid,omitempty"`
+	Identities          []UserIdentity          `json:"identities"`
+	APIKeys             []APIKey                `json:"apiKeys"`
+	AuditEntries        []AuditEntry            `json:"auditEntries"` // Changes to the user's data and changes made by the user
+	PIIAccesses         []PIIAccess             `json:"piiAccesses"`  // Reads of the user's data and reads made by the user
 }
@@ -12,11 +12,29 @@ import (
 
 func (s *Server) RegisterRoutes(h *handler.Handler) http.Handler {
 	r := gin.Default()
+	r.Use(h.RequestInfo())
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := r.Group("/api")
+	public := r.Group("/api")
 	{
+		oidc := public.Group("/auth/oidc")
+		{
+			oidc.GET("/login", h.OIDCLogin)       // Redirect to the identity provider sign-in
+			oidc.GET("/callback", h.OIDCCallback) // Exchange the provider code for a session token
+		}
+	}
+
+	api := r.Group("/api", h.Authenticate())
+	{
+		auth := api.Group("/auth")
+		{
+			auth.GET("/me", h.GetPrincipal)                 // Get the authenticated caller
+			auth.POST("/api-keys", h.CreateAPIKey)          // Issue an API key acting as the caller
+			auth.GET("/api-keys", h.GetAPIKeys)             // Get the API keys issued by the caller
+			auth.DELETE("/api-keys/:keyId", h.RevokeAPIKey) // Revoke an API key by key id
+		}
+
 		users := api.Group("/users")
 		{
 			users.GET("/info", h.GetUserByPassportNumber) // Check for user existence by passportNumber
@@ -26,18 +44,73 @@ func (s *Server) RegisterRoutes(h *handler.Handler) http.Handler {
 
 			userID := users.Group("/:id")
 			{
-				userID.GET("", h.GetUser)       // Get a user data by user id
-				userID.PATCH("", h.UpdateUser)  // Update a user data by user id
-				userID.DELETE("", h.DeleteUser) // Delete a user by user id
+				userID.GET("", h.GetUser)                     // Get a user data by user id
+				userID.PATCH("", h.UpdateUser)                // Update a user data by user id
+				userID.DELETE("", h.DeleteUser)               // Deactivate a user by user id
+				userID.POST("/restore", h.RestoreUser)        // Reactivate a deactivated user
+				userID.POST("/purge", h.PurgeUser)            // Delete a deactivated user with all their data
+				userID.PUT("/role", h.SetUserRole)            // Assign the role, manager and permissions of a user
+				userID.GET("/pii-accesses", h.GetPIIAccesses) // Get the full reads of a user personal data
+				userID.GET("/export", h.ExportUserData)       // Download everything stored about a user
+				userID.POST("/anonymize", h.AnonymizeUser)    // Remove the personal data of a user for good
+
+				userID.PUT("/holiday-calendar", h.SetUserHolidayCalendar)            // Assign a holiday calendar to a user
+				userID.DELETE("/holiday-calendar", h.DeleteUserHolidayCalendar)      // Unassign the holiday calendar of a user
+				userID.PUT("/team/holiday-calendar", h.SetTeamHolidayCalendar)       // Assign a holiday calendar to the team of a manager
+				userID.DELETE("/team/holiday-calendar", h.DeleteTeamHolidayCalendar) // Unassign the holiday calendar of the team of a manager
 
 				tasks := userID.Group("/tasks")
 				{
-					tasks.POST("/start", h.StartTimeTask)  // Start task time tracking for a user
-					tasks.POST("/stop", h.StopTimeTask)    // Stop task time tracking for a user
-					tasks.GET("/result", h.GetTasksResult) // Get users result for a period
+					tasks.POST("/start", h.Idempotency(), h.StartTimeTask)       // Start task time tracking for a user
+					tasks.POST("/stop", h.Idempotency(), h.StopTimeTask)         // Stop task time tracking for a user
+					tasks.POST("/switch", h.Idempotency(), h.SwitchTimeTask)     // Stop the active task and start a new one at the same instant
+					tasks.POST("/continue", h.Idempotency(), h.ContinueTimeTask) // Start again the latest or a given recorded task
+					tasks.GET("/result", h.GetTasksResult)                       // Get users result for a period
+					tasks.GET("/search", h.SearchTasks)                          // Full-text search over task names, descriptions and notes
+					tasks.GET("/suggestions", h.SuggestTaskNames)                // Suggest task names from the user history
+					tasks.GET("/history/:entryId", h.GetTaskHistoryEntry)        // Get a recorded task with its ETag
+					tasks.PATCH("/history/:entryId", h.UpdateTaskHistoryEntry)   // Edit a recorded task, honoring If-Match
+				}
+
+				userID.GET("/pomodoro", h.GetPomodoroSettings)      // Get a user pomodoro cycle settings
+				userID.PATCH("/pomodoro", h.UpdatePomodoroSettings) // Update a user pomodoro cycle settings
+
+				budgets := userID.Group("/budgets")
+				{
+					budgets.PUT("", h.SetTaskBudget)                 // Set an estimate for a task
+					budgets.GET("", h.GetTaskBudgets)                // Get estimate, actual and remaining time per budget
+					budgets.DELETE("/:budgetId", h.DeleteTaskBudget) // Delete a budget by budget id
+				}
+
+				schedules := userID.Group("/schedules")
+				{
+					schedules.POST("", h.SetWorkSchedule)                  // Add a schedule version effective from a date
+					schedules.GET("", h.GetWorkSchedules)                  // Get all schedule versions of a user
+					schedules.DELETE("/:scheduleId", h.DeleteWorkSchedule) // Delete a schedule version by schedule id
+				}
+
+				userID.GET("/overtime", h.GetOvertimeReport) // Get expected, actual, overtime and undertime for a period
+
+				absences := userID.Group("/absences")
+				{
+					absences.POST("", h.CreateAbsence)              // Record a vacation, sick leave or other absence
+					absences.GET("", h.GetAbsences)                 // Get absences of a user in a year
+					absences.DELETE("/:absenceId", h.DeleteAbsence) // Delete an absence by absence id
+					absences.PUT("/balances", h.SetAbsenceBalance)  // Set the yearly allowance of an absence type
+					absences.GET("/balances", h.GetAbsenceBalances) // Get allowance, used and remaining days per type
 				}
 			}
 		}
+
+		holidayCalendars := api.Group("/holiday-calendars")
+		{
+			holidayCalendars.POST("", h.ImportHolidayCalendar)               // Import a holiday calendar from an ICS or JSON file
+			holidayCalendars.GET("", h.GetHolidayCalendars)                  // Get all holiday calendars
+			holidayCalendars.GET("/:calendarId/holidays", h.GetHolidays)     // Get the holidays of a calendar in a year
+			holidayCalendars.DELETE("/:calendarId", h.DeleteHolidayCalendar) // Delete a holiday calendar by calendar id
+		}
+
+		api.GET("/audit-log", h.GetAuditEntries) // Get the changes to users, tasks and history entries
 	}
 
 	return r
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrAbsenceNotFound = errors.New("absence not found")
+	ErrAbsenceOverlaps = errors.New("absence overlaps an existing absence")
+	ErrUserAbsent      = errors.New("user is absent")
+)
+
+type AbsenceService struct {
+	repository db.Store
+}
+
+func NewAbsenceService(repository db.Store) *AbsenceService {
+	return &AbsenceService{
+		repository: repository,
+	}
+}
+
+func (as *AbsenceService) CreateAbsence(ctx context.Context, userUUID uuid.UUID, startDate, endDate time.Time, payload *models.CreateAbsencePayload) (*models.Absence, error) {
+	if err := authorizeUser(ctx, as.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	params := db.CreateAbsenceParams{
+		UserUuid:     userPgUUID,
+		Type:         payload.Type,
+		StartDate:    pgtype.Date{Time: startDate, Valid: true},
+		EndDate:      pgtype.Date{Time: endDate, Valid: true},
+		HalfDayStart: payload.HalfDayStart,
+		HalfDayEnd:   payload.HalfDayEnd,
+		Note:         utils.ToPgText(payload.Note),
+	}
+
+	// Serializable isolation keeps two concurrent requests from both passing
+	// the overlap check.
+	var absenceRaw db.Absence
+	err := as.repository.ExecTxWithIsolation(ctx, pgx.Serializable, func(q db.Querier) error {
+		overlaps, err := q.HasOverlappingAbsence(ctx, db.HasOverlappingAbsenceParams{
+			UserUuid:  userPgUUID,
+			StartDate: params.StartDate,
+			EndDate:   params.EndDate,
+		})
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return ErrAbsenceOverlaps
+		}
+
+		absenceRaw, err = q.CreateAbsence(ctx, params)
+		if err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	absence, err := utils.ConvertDBAbsenceToModelsAbsence(absenceRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting absence: %v", err)
+	}
+
+	return absence, nil
+}
+
+func (as *AbsenceService) GetAbsences(ctx context.Context, userUUID uuid.UUID, year int) ([]models.Absence, error) {
+	if err := authorizeUser(ctx, as.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := as.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	from, to := yearBounds(year)
+	absencesRaw, err := as.repository.GetAbsencesInRange(ctx, db.GetAbsencesInRangeParams{
+		UserUuid: userPgUUID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	absences := make([]models.Absence, len(absencesRaw))
+	for i, absenceRaw := range absencesRaw {
+		absence, err := utils.ConvertDBAbsenceToModelsAbsence(absenceRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting absence: %v", err)
+		}
+		absences[i] = *absence
+	}
+
+	return absences, nil
+}
+
+func (as *AbsenceService) DeleteAbsence(ctx context.Context, userUUID, absenceUUID uuid.UUID) error {
+	if err := authorizeUser(ctx, as.repository, userUUID, accessWrite); err != nil {
+		return err
+	}
+
+	params := db.DeleteAbsenceParams{
+		AbsenceUuid: pgtype.UUID{Bytes: absenceUUID, Valid: true},
+		UserUuid:    pgtype.UUID{Bytes: userUUID, Valid: true},
+	}
+
+	deleted, err := as.repository.DeleteAbsence(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrAbsenceNotFound
+	}
+
+	return nil
+}
+
+func (as *AbsenceService) SetAbsenceBalance(ctx context.Context, userUUID uuid.UUID, payload *models.AbsenceBalancePayload) error {
+	if err := authorizeUser(ctx, as.repository, userUUID, accessManage); err != nil {
+		return err
+	}
+
+	params := db.UpsertAbsenceBalanceParams{
+		UserUuid:      pgtype.UUID{Bytes: userUUID, Valid: true},
+		Year:          int32(payload.Year),
+		Type:          payload.Type,
+		AllowanceDays: payload.AllowanceDays,
+	}
+
+	if _, err := as.repository.UpsertAbsenceBalance(ctx, params); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetAbsenceBalances returns the allowance, used and remaining days per absence
+// type in a year. Only working days of the user count as used.
+func (as *AbsenceService) GetAbsenceBalances(ctx context.Context, userUUID uuid.UUID, year int) ([]models.AbsenceBalance, error) {
+	if err := authorizeUser(ctx, as.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := as.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	balancesRaw, err := as.repository.GetAbsenceBalances(ctx, db.GetAbsenceBalancesParams{
+		UserUuid: userPgUUID,
+		Year:     int32(year),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := yearBounds(year)
+	calendar, err := newWorkCalendar(ctx, as.repository, userPgUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*models.AbsenceBalance)
+	for _, balanceRaw := range balancesRaw {
+		balances[balanceRaw.Type] = &models.AbsenceBalance{
+			Year:          year,
+			Type:          balanceRaw.Type,
+			AllowanceDays: balanceRaw.AllowanceDays,
+		}
+	}
+
+	for _, absence := range calendar.absences {
+		balance, ok := balances[absence.Type]
+		if !ok {
+			balance = &models.AbsenceBalance{Year: year, Type: absence.Type}
+			balances[absence.Type] = balance
+		}
+
+		start := maxTime(absence.StartDate.Time, from)
+		end := minTime(absence.EndDate.Time, to)
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if calendar.isWorkingDay(day) {
+				balance.UsedDays += absenceDayFraction(absence, day)
+			}
+		}
+	}
+
+	result := make([]models.AbsenceBalance, 0, len(balances))
+	for _, balance := range balances {
+		balance.RemainingDays = balance.AllowanceDays - balance.UsedDays
+		result = append(result, *balance)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+
+	return result, nil
+}
+
+func yearBounds(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
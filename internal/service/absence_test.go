@@ -0,0 +1,44 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+)
+
+func TestGetAbsenceBalances(t *testing.T) {
+	store := overtimeStore()
+	store.absences = []db.Absence{
+		{
+			// Monday to Friday around the May 1 holiday, leaving Friday
+			// afternoon.
+			Type:       "vacation",
+			StartDate:  pgDate(2024, time.April, 29),
+			EndDate:    pgDate(2024, time.May, 3),
+			HalfDayEnd: true,
+		},
+		{
+			// Friday to Monday over a weekend.
+			Type:      "sick",
+			StartDate: pgDate(2024, time.May, 10),
+			EndDate:   pgDate(2024, time.May, 13),
+		},
+	}
+	store.absenceBalances = []db.AbsenceBalance{{Year: 2024, Type: "vacation", AllowanceDays: 20}}
+	as := NewAbsenceService(store)
+
+	balances, err := as.GetAbsenceBalances(userContext(), testUserUUID, 2024)
+	if err != nil {
+		t.Fatalf("GetAbsenceBalances() error = %v", err)
+	}
+
+	want := []models.AbsenceBalance{
+		{Year: 2024, Type: "sick", UsedDays: 2, RemainingDays: -2},
+		{Year: 2024, Type: "vacation", AllowanceDays: 20, UsedDays: 3.5, RemainingDays: 16.5},
+	}
+	if !slices.Equal(balances, want) {
+		t.Fatalf("GetAbsenceBalances() = %+v, want %+v", balances, want)
+	}
+}
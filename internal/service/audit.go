@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// auditSystemActor is the actor recorded for the changes made by background
+// jobs.
+const auditSystemActor = "system"
+
+var ErrAuditUserNotFound = errors.New("audited user not found")
+
+// RequestInfo describes the request a change is made for.
+type RequestInfo struct {
+	ID       string
+	SourceIP string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying info, recorded in the audit
+// log.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+type AuditService struct {
+	repository db.Store
+}
+
+func NewAuditService(repository db.Store) *AuditService {
+	return &AuditService{
+		repository: repository,
+	}
+}
+
+// GetAuditEntries lists the audit log of the organization of the caller,
+// latest first. Only admins may read the log.
+func (as *AuditService) GetAuditEntries(ctx context.Context, filter *models.AuditFilter, limit, offset int) ([]models.AuditEntry, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetAuditEntriesParams{
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		Entity:           utils.ToPgText(filter.Entity),
+		EntityUuid:       utils.ToPgUUID(filter.EntityUUID),
+		UserUuid:         utils.ToPgUUID(filter.UserUUID),
+		Actor:            utils.ToPgText(filter.Actor),
+		Action:           utils.ToPgText(filter.Action),
+		EntryLimit:       int32(limit),
+		EntryOffset:      int32(offset),
+	}
+	if filter.From != nil {
+		params.FromTime = pgtype.Timestamptz{Time: filter.From.UTC(), Valid: true}
+	}
+	if filter.To != nil {
+		params.ToTime = pgtype.Timestamptz{Time: filter.To.UTC(), Valid: true}
+	}
+
+	entriesRaw, err := as.repository.GetAuditEntries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.AuditEntry, len(entriesRaw))
+	for i, entryRaw := range entriesRaw {
+		entries[i] = utils.ConvertDBAuditLogToModelsAuditEntry(entryRaw)
+	}
+	return entries, nil
+}
+
+// recordAudit appends a change to an entity of the user userUUID to the audit
+// log, through repository so that it commits or rolls back with the change.
+// before is nil for creations and after is nil for deletions. Updates keep the
+// members that changed only, and are not recorded when none did.
+func recordAudit(ctx context.Context, repository db.Querier, entity string, entityUUID, userUUID uuid.UUID, before, after any) error {
+	action := models.AuditActionUpdate
+	switch {
+	case before == nil:
+		action = models.AuditActionCreate
+	case after == nil:
+		action = models.AuditActionDelete
+	}
+
+	return recordAuditAction(ctx, repository, action, entity, entityUUID, userUUID, before, after)
+}
+
+// recordAuditAction is recordAudit for the actions not told apart by the
+// snapshots given, such as the deactivation of a user.
+func recordAuditAction(ctx context.Context, repository db.Querier, action, entity string, entityUUID, userUUID uuid.UUID, before, after any) error {
+	beforeJSON, afterJSON, err := auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("error computing audit diff: %w", err)
+	}
+	if action == models.AuditActionUpdate && beforeJSON == nil && afterJSON == nil {
+		return nil
+	}
+
+	info := requestInfoFromContext(ctx)
+	params := db.CreateAuditEntryParams{
+		Actor:      auditSystemActor,
+		RequestID:  utils.ToPgText(nonEmpty(info.ID)),
+		SourceIp:   utils.ToPgText(nonEmpty(info.SourceIP)),
+		Entity:     entity,
+		EntityUuid: pgtype.UUID{Bytes: entityUUID, Valid: true},
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		UserUuid:   pgtype.UUID{Bytes: userUUID, Valid: true},
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		params.Actor = principal.Subject
+		params.ActorUserUuid = utils.ToPgUUID(principal.UserUUID)
+		params.ApiKeyUuid = utils.ToPgUUID(principal.APIKeyUUID)
+	}
+
+	recorded, err := repository.CreateAuditEntry(ctx, params)
+	if err != nil {
+		return err
+	}
+	if recorded == 0 {
+		return ErrAuditUserNotFound
+	}
+	return nil
+}
+
+// auditDiff returns the JSON objects recorded for a change from before to
+// after. For updates both only hold the members whose value changed, and are
+// nil when nothing did.
+func auditDiff(before, after any) ([]byte, []byte, error) {
+	beforeMembers, err := auditMembers(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterMembers, err := auditMembers(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeMembers != nil && afterMembers != nil {
+		for name, value := range beforeMembers {
+			if other, ok := afterMembers[name]; ok && bytes.Equal(value, other) {
+				delete(beforeMembers, name)
+				delete(afterMembers, name)
+			}
+		}
+		if len(beforeMembers) == 0 && len(afterMembers) == 0 {
+			return nil, nil, nil
+		}
+	}
+
+	beforeJSON, err := marshalAuditMembers(beforeMembers)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditMembers(afterMembers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func auditMembers(snapshot any) (map[string]json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = map[string]json.RawMessage{}
+	}
+	return members, nil
+}
+
+func marshalAuditMembers(members map[string]json.RawMessage) ([]byte, error) {
+	if members == nil {
+		return nil, nil
+	}
+	return json.Marshal(members)
+}
+
+// auditTask records a change to the active task of a user.
+func auditTask(ctx context.Context, repository db.Querier, before, after *db.Task) error {
+	var (
+		userUUID, taskUUID uuid.UUID
+		snapshots          [2]any
+	)
+	for i, taskRaw := range []*db.Task{before, after} {
+		if taskRaw == nil {
+			continue
+		}
+		task, err := utils.ConvertDBTaskToModelsTask(*taskRaw)
+		if err != nil {
+			return fmt.Errorf("error converting task: %v", err)
+		}
+		userUUID, taskUUID = task.UserUUID, task.UUID
+		snapshots[i] = task
+	}
+
+	return recordAudit(ctx, repository, models.AuditEntityTask, taskUUID, userUUID, snapshots[0], snapshots[1])
+}
+
+// auditTaskHistory records a change to a history entry.
+func auditTaskHistory(ctx context.Context, repository db.Querier, before, after *db.GetTaskHistoryByUUIDRow) error {
+	var (
+		userUUID, entryUUID uuid.UUID
+		snapshots           [2]any
+	)
+	for i, entryRaw := range []*db.GetTaskHistoryByUUIDRow{before, after} {
+		if entryRaw == nil {
+			continue
+		}
+		entry, err := utils.ConvertDBTaskHistoryEntryToModelsTaskHistory(*entryRaw)
+		if err != nil {
+			return fmt.Errorf("error converting task history entry: %v", err)
+		}
+		userUUID, entryUUID = entry.UserUuid, entry.Uuid
+		snapshots[i] = entry
+	}
+
+	return recordAudit(ctx, repository, models.AuditEntityTaskHistory, entryUUID, userUUID, snapshots[0], snapshots[1])
+}
+
+// createdTaskHistoryEntry returns the entry inserted by CreateTaskHistory.
+func createdTaskHistoryEntry(created db.CreateTaskHistoryRow) *db.GetTaskHistoryByUUIDRow {
+	return &db.GetTaskHistoryByUUIDRow{
+		Uuid:        created.Uuid,
+		UserUuid:    created.UserUuid,
+		Name:        created.Name,
+		Description: created.Description,
+		Notes:       created.Notes,
+		StartTime:   created.StartTime,
+		EndTime:     created.EndTime,
+		Mode:        created.Mode,
+		Version:     created.Version,
+	}
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
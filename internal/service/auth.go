@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/jwt"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+// API keys look like tt_<prefix>_<secret>. The prefix identifies the key and
+// only the SHA-256 hash of the whole key is stored.
+const (
+	apiKeyMarker       = "tt_"
+	apiKeyPrefixBytes  = 6
+	apiKeySecretBytes  = 32
+	apiKeyPrefixLength = apiKeyPrefixBytes * 2
+)
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller, whose
+// queries are restricted to the organization of the caller.
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	ctx = db.WithOrganization(ctx, principal.OrganizationUUID)
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller stored by WithPrincipal.
+func PrincipalFromContext(ctx context.Context) (*models.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*models.Principal)
+	return principal, ok && principal != nil
+}
+
+type AuthService struct {
+	repository db.Querier
+	system     db.Querier // Sees every organization, to resolve callers
+	verifier   *jwt.Verifier
+}
+
+func NewAuthService(repository, system db.Querier, verifier *jwt.Verifier) *AuthService {
+	return &AuthService{
+		repository: repository,
+		system:     system,
+		verifier:   verifier,
+	}
+}
+
+// Authenticate resolves the caller of a bearer token, which is either an API
+// key or a signed JWT.
+func (as *AuthService) Authenticate(ctx context.Context, token string) (*models.Principal, error) {
+	if strings.HasPrefix(token, apiKeyMarker) {
+		return as.authenticateAPIKey(ctx, token)
+	}
+	return as.authenticateJWT(ctx, token)
+}
+
+// privateClaims holds the private claims read from JWTs.
+type privateClaims struct {
+	Role         string `json:"role"`
+	Organization string `json:"org"`
+	Scope        string `json:"scope"`
+}
+
+// authenticateJWT takes the role, permissions and organization of a subject
+// that is a user from the users table. Other subjects, such as service
+// accounts, get the role and scope claims of the token and must name their
+// organization in the org claim.
+func (as *AuthService) authenticateJWT(ctx context.Context, token string) (*models.Principal, error) {
+	if !as.verifier.Enabled() {
+		return nil, ErrUnauthenticated
+	}
+
+	var private privateClaims
+	claims, err := as.verifier.Verify(token, &private)
+	if err != nil {
+		logrus.Infof("Rejected JWT: %v", err)
+		return nil, ErrUnauthenticated
+	}
+	if claims.Subject == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	principal := &models.Principal{
+		Subject: claims.Subject,
+		Method:  models.AuthMethodJWT,
+		Role:    models.RoleEmployee,
+	}
+
+	if userUUID, err := uuid.Parse(claims.Subject); err == nil {
+		userRaw, err := as.system.GetUserByUUID(ctx, pgtype.UUID{Bytes: userUUID, Valid: true})
+		if err == nil {
+			if userRaw.DeletedAt.Valid {
+				return nil, ErrUnauthenticated
+			}
+			organizationUUID := uuid.UUID(userRaw.OrganizationUuid.Bytes)
+			if private.Organization != "" && private.Organization != organizationUUID.String() {
+				return nil, ErrUnauthenticated
+			}
+			principal.UserUUID = &userUUID
+			principal.Role = userRaw.Role
+			principal.Permissions = userRaw.Permissions
+			principal.OrganizationUUID = organizationUUID
+			return principal, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	organizationUUID, err := uuid.Parse(private.Organization)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	if _, err := as.system.GetOrganizationByUUID(ctx, pgtype.UUID{Bytes: organizationUUID, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUnauthenticated
+		}
+		return nil, err
+	}
+	principal.OrganizationUUID = organizationUUID
+
+	if private.Role == models.RoleAdmin || private.Role == models.RoleManager {
+		principal.Role = private.Role
+	}
+	// Permissions are granted in the space-separated scope claim.
+	for _, scope := range strings.Fields(private.Scope) {
+		if scope == models.PermissionPIIRead {
+			principal.Permissions = append(principal.Permissions, scope)
+		}
+	}
+
+	return principal, nil
+}
+
+func (as *AuthService) authenticateAPIKey(ctx context.Context, key string) (*models.Principal, error) {
+	prefix, ok := apiKeyPrefix(key)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	keyRaw, err := as.system.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUnauthenticated
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(keyRaw.KeyHash)) != 1 {
+		return nil, ErrUnauthenticated
+	}
+	if keyRaw.RevokedAt.Valid || (keyRaw.ExpiresAt.Valid && time.Now().After(keyRaw.ExpiresAt.Time)) {
+		return nil, ErrUnauthenticated
+	}
+
+	if err := as.system.TouchAPIKey(ctx, keyRaw.Uuid); err != nil {
+		logrus.Errorf("Error updating API key usage: %v", err)
+	}
+
+	keyUUID := uuid.UUID(keyRaw.Uuid.Bytes)
+	principal := &models.Principal{
+		Subject:          keyRaw.CreatedBy,
+		Method:           models.AuthMethodAPIKey,
+		Role:             keyRaw.Role,
+		Permissions:      keyRaw.Permissions,
+		OrganizationUUID: uuid.UUID(keyRaw.OrganizationUuid.Bytes),
+		APIKeyUUID:       &keyUUID,
+	}
+	// Keys of users follow the current role and permissions of the user rather
+	// than the ones they had when the key was issued.
+	if keyRaw.UserUuid.Valid {
+		userRaw, err := as.system.GetUserByUUID(ctx, keyRaw.UserUuid)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrUnauthenticated
+			}
+			return nil, err
+		}
+		if userRaw.DeletedAt.Valid {
+			return nil, ErrUnauthenticated
+		}
+		userUUID := uuid.UUID(keyRaw.UserUuid.Bytes)
+		principal.UserUUID = &userUUID
+		principal.Role = userRaw.Role
+		principal.Permissions = userRaw.Permissions
+	}
+
+	return principal, nil
+}
+
+// CreateAPIKey issues a key acting as the calling principal. The secret is
+// only part of the result, it cannot be retrieved later.
+func (as *AuthService) CreateAPIKey(ctx context.Context, payload *models.CreateAPIKeyPayload) (*models.CreatedAPIKey, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	key, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.CreateAPIKeyParams{
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		Name:             payload.Name,
+		Prefix:           prefix,
+		KeyHash:          hashAPIKey(key),
+		CreatedBy:        principal.Subject,
+		Role:             principal.Role,
+		Permissions:      append([]string{}, principal.Permissions...),
+	}
+	if principal.UserUUID != nil {
+		params.UserUuid = pgtype.UUID{Bytes: *principal.UserUUID, Valid: true}
+	}
+	if payload.ExpiresAt != nil {
+		params.ExpiresAt = pgtype.Timestamptz{Time: *payload.ExpiresAt, Valid: true}
+	}
+
+	keyRaw, err := as.repository.CreateAPIKey(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := utils.ConvertDBAPIKeyToModelsAPIKey(keyRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting api key: %v", err)
+	}
+
+	return &models.CreatedAPIKey{APIKey: *apiKey, Key: key}, nil
+}
+
+// GetAPIKeys lists the keys issued by the calling principal in its
+// organization.
+func (as *AuthService) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	keysRaw, err := as.repository.GetAPIKeysByCreator(ctx, db.GetAPIKeysByCreatorParams{
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		CreatedBy:        principal.Subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]models.APIKey, len(keysRaw))
+	for i, keyRaw := range keysRaw {
+		key, err := utils.ConvertDBAPIKeyToModelsAPIKey(keyRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting api key: %v", err)
+		}
+		keys[i] = *key
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey disables a key issued by the calling principal in its
+// organization.
+func (as *AuthService) RevokeAPIKey(ctx context.Context, keyUUID uuid.UUID) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	revoked, err := as.repository.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{
+		ApiKeyUuid:       pgtype.UUID{Bytes: keyUUID, Valid: true},
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		CreatedBy:        principal.Subject,
+	})
+	if err != nil {
+		return err
+	}
+
+	if revoked == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func generateAPIKey() (key, prefix string, err error) {
+	random := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(random[:apiKeyPrefixBytes])
+	secret := base64.RawURLEncoding.EncodeToString(random[apiKeyPrefixBytes:])
+	return apiKeyMarker + prefix + "_" + secret, prefix, nil
+}
+
+func apiKeyPrefix(key string) (string, bool) {
+	rest := strings.TrimPrefix(key, apiKeyMarker)
+	if len(rest) <= apiKeyPrefixLength || rest[apiKeyPrefixLength] != '_' {
+		return "", false
+	}
+	return rest[:apiKeyPrefixLength], true
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
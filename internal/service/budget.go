@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// budgetThresholds are the consumed percentages that fire a BudgetEvent,
+// checked from the highest down so a single jump reports only the top one.
+var budgetThresholds = []int{100, 80}
+
+// BudgetNotifier is the hook that receives budget threshold events.
+type BudgetNotifier interface {
+	NotifyBudgetThreshold(ctx context.Context, event models.BudgetEvent) error
+}
+
+type BudgetService struct {
+	repository db.Store
+	system     db.Store // Sees every organization, for the budget watcher
+	notifier   BudgetNotifier
+}
+
+func NewBudgetService(repository, system db.Store, notifier BudgetNotifier) *BudgetService {
+	return &BudgetService{
+		repository: repository,
+		system:     system,
+		notifier:   notifier,
+	}
+}
+
+func (bs *BudgetService) SetTaskBudget(ctx context.Context, userUUID uuid.UUID, payload *models.TaskBudgetPayload) (*models.TaskBudget, error) {
+	if err := authorizeUser(ctx, bs.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	params := db.UpsertTaskBudgetParams{
+		UserUuid:        userPgUUID,
+		Name:            payload.Name,
+		EstimateMinutes: int32(payload.EstimateMinutes),
+	}
+
+	var usageRaw db.GetTaskBudgetUsageByNameRow
+	err := bs.repository.ExecTx(ctx, func(q db.Querier) error {
+		if _, err := q.UpsertTaskBudget(ctx, params); err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var err error
+		usageRaw, err = q.GetTaskBudgetUsageByName(ctx, db.GetTaskBudgetUsageByNameParams{
+			UserUuid: userPgUUID,
+			Name:     payload.Name,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := utils.ConvertDBTaskBudgetUsageToModelsTaskBudget(db.GetTaskBudgetsUsageRow(usageRaw))
+	if err != nil {
+		return nil, fmt.Errorf("error converting budget: %v", err)
+	}
+
+	return budget, nil
+}
+
+func (bs *BudgetService) GetTaskBudgets(ctx context.Context, userUUID uuid.UUID) ([]models.TaskBudget, error) {
+	if err := authorizeUser(ctx, bs.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := bs.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return getTaskBudgets(ctx, bs.repository, userPgUUID)
+}
+
+func (bs *BudgetService) DeleteTaskBudget(ctx context.Context, userUUID, budgetUUID uuid.UUID) error {
+	if err := authorizeUser(ctx, bs.repository, userUUID, accessWrite); err != nil {
+		return err
+	}
+
+	params := db.DeleteTaskBudgetParams{
+		BudgetUuid: pgtype.UUID{Bytes: budgetUUID, Valid: true},
+		UserUuid:   pgtype.UUID{Bytes: userUUID, Valid: true},
+	}
+
+	deleted, err := bs.repository.DeleteTaskBudget(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// CheckRunningBudgets fires threshold events for budgets whose tasks are
+// currently running. A failed budget is logged and the others still checked.
+func (bs *BudgetService) CheckRunningBudgets(ctx context.Context) error {
+	usagesRaw, err := bs.system.GetRunningTaskBudgetsUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, usageRaw := range usagesRaw {
+		if err := checkBudgetThreshold(ctx, bs.system, bs.notifier, db.GetTaskBudgetsUsageRow(usageRaw)); err != nil {
+			logrus.Errorf("Error checking budget for task %q: %v", usageRaw.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// WatchRunningBudgets calls CheckRunningBudgets every interval until ctx is done.
+func (bs *BudgetService) WatchRunningBudgets(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := bs.CheckRunningBudgets(ctx); err != nil {
+				logrus.Errorf("Error checking running budgets: %v", err)
+			}
+		}
+	}
+}
+
+func getTaskBudgets(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID) ([]models.TaskBudget, error) {
+	usagesRaw, err := repository.GetTaskBudgetsUsage(ctx, userPgUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := make([]models.TaskBudget, len(usagesRaw))
+	for i, usageRaw := range usagesRaw {
+		budget, err := utils.ConvertDBTaskBudgetUsageToModelsTaskBudget(usageRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting budget: %v", err)
+		}
+		budgets[i] = *budget
+	}
+
+	return budgets, nil
+}
+
+// checkBudgetThreshold notifies about the highest threshold the budget has
+// crossed since the last notification and remembers it.
+func checkBudgetThreshold(ctx context.Context, repository db.Querier, notifier BudgetNotifier, usage db.GetTaskBudgetsUsageRow) error {
+	budget, err := utils.ConvertDBTaskBudgetUsageToModelsTaskBudget(usage)
+	if err != nil {
+		return fmt.Errorf("error converting budget: %v", err)
+	}
+
+	for _, threshold := range budgetThresholds {
+		if budget.ConsumedPercent < threshold || int(usage.NotifiedThreshold) >= threshold {
+			continue
+		}
+
+		var userUUID uuid.UUID
+		if err := userUUID.UnmarshalBinary(usage.UserUuid.Bytes[:]); err != nil {
+			return err
+		}
+
+		event := models.BudgetEvent{
+			BudgetUUID:      budget.UUID,
+			UserUUID:        userUUID,
+			Name:            budget.Name,
+			Threshold:       threshold,
+			ConsumedPercent: budget.ConsumedPercent,
+			Estimate:        budget.Estimate,
+			Actual:          budget.Actual,
+			OccurredAt:      time.Now().UTC(),
+		}
+		if err := notifier.NotifyBudgetThreshold(ctx, event); err != nil {
+			return fmt.Errorf("error notifying budget threshold: %w", err)
+		}
+
+		return repository.UpdateTaskBudgetNotifiedThreshold(ctx, db.UpdateTaskBudgetNotifiedThresholdParams{
+			NotifiedThreshold: int32(threshold),
+			BudgetUuid:        usage.Uuid,
+		})
+	}
+
+	return nil
+}
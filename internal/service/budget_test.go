@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type fakeNotifier struct {
+	events []models.BudgetEvent
+	err    error
+}
+
+func (n *fakeNotifier) NotifyBudgetThreshold(ctx context.Context, event models.BudgetEvent) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+// budgetUsage returns the usage of a one hour budget consumed at percent.
+func budgetUsage(percent, notifiedThreshold int) db.GetTaskBudgetsUsageRow {
+	return db.GetTaskBudgetsUsageRow{
+		Uuid:              pgUUID(uuid.New()),
+		UserUuid:          pgUUID(testUserUUID),
+		Name:              "coding",
+		EstimateMinutes:   60,
+		NotifiedThreshold: int32(notifiedThreshold),
+		ActualSeconds:     int64(percent) * 36,
+	}
+}
+
+func TestCheckBudgetThreshold(t *testing.T) {
+	tests := []struct {
+		name              string
+		percent           int
+		notifiedThreshold int
+		want              []int
+	}{
+		{"below every threshold", 50, 0, nil},
+		{"crosses 80", 85, 0, []int{80}},
+		{"80 already notified", 95, 80, nil},
+		{"crosses 100 after 80", 100, 80, []int{100}},
+		// A single jump reports the highest threshold only.
+		{"jumps past 100", 150, 0, []int{100}},
+		{"100 already notified", 150, 100, nil},
+		// A changed estimate resets the notified threshold, so the crossed
+		// thresholds are reported again.
+		{"reset after an estimate change", 90, 0, []int{80}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{}
+			notifier := &fakeNotifier{}
+			usage := budgetUsage(tt.percent, tt.notifiedThreshold)
+
+			if err := checkBudgetThreshold(context.Background(), store, notifier, usage); err != nil {
+				t.Fatalf("checkBudgetThreshold() error = %v", err)
+			}
+
+			var notified []int
+			for _, event := range notifier.events {
+				notified = append(notified, event.Threshold)
+				if event.UserUUID != testUserUUID || event.BudgetUUID != uuid.UUID(usage.Uuid.Bytes) || event.ConsumedPercent != tt.percent {
+					t.Fatalf("checkBudgetThreshold() event = %+v", event)
+				}
+			}
+			if !slices.Equal(notified, tt.want) {
+				t.Fatalf("checkBudgetThreshold() notified %v, want %v", notified, tt.want)
+			}
+
+			var remembered []int
+			for _, threshold := range store.notifiedThresholds {
+				remembered = append(remembered, int(threshold))
+			}
+			if !slices.Equal(remembered, tt.want) {
+				t.Fatalf("checkBudgetThreshold() remembered %v, want %v", remembered, tt.want)
+			}
+		})
+	}
+}
+
+// A failed notification is not remembered, so the next check sends it again.
+func TestCheckBudgetThresholdRetriesFailedNotifications(t *testing.T) {
+	store := &fakeStore{}
+	notifier := &fakeNotifier{err: errors.New("webhook unavailable")}
+	usage := budgetUsage(85, 0)
+
+	if err := checkBudgetThreshold(context.Background(), store, notifier, usage); err == nil {
+		t.Fatal("checkBudgetThreshold() succeeded, want the notifier error")
+	}
+	if len(store.notifiedThresholds) != 0 {
+		t.Fatalf("checkBudgetThreshold() remembered %v after a failed notification", store.notifiedThresholds)
+	}
+
+	notifier.err = nil
+	if err := checkBudgetThreshold(context.Background(), store, notifier, usage); err != nil {
+		t.Fatalf("checkBudgetThreshold() error = %v", err)
+	}
+	if len(notifier.events) != 1 || notifier.events[0].Threshold != 80 {
+		t.Fatalf("checkBudgetThreshold() notified %+v, want the 80 threshold", notifier.events)
+	}
+}
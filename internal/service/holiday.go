@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/holidays"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrHolidayCalendarNotFound = errors.New("holiday calendar not found")
+
+type HolidayService struct {
+	repository db.Store
+}
+
+func NewHolidayService(repository db.Store) *HolidayService {
+	return &HolidayService{
+		repository: repository,
+	}
+}
+
+// ImportHolidayCalendar creates the calendar with the given name in the
+// organization of the caller or replaces all holidays of an existing one.
+func (hs *HolidayService) ImportHolidayCalendar(ctx context.Context, name string, region *string, days []holidays.Holiday) (*models.HolidayCalendarImport, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		calendarRaw db.HolidayCalendar
+		imported    int64
+	)
+	err = hs.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		calendarRaw, err = q.UpsertHolidayCalendar(ctx, db.UpsertHolidayCalendarParams{
+			OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+			Name:             name,
+			Region:           utils.ToPgText(region),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteHolidays(ctx, calendarRaw.Uuid); err != nil {
+			return err
+		}
+
+		params := db.CreateHolidaysParams{
+			CalendarUuid: calendarRaw.Uuid,
+			Days:         make([]pgtype.Date, len(days)),
+			Names:        make([]string, len(days)),
+		}
+		for i, day := range days {
+			params.Days[i] = pgtype.Date{Time: day.Date, Valid: true}
+			params.Names[i] = day.Name
+		}
+
+		imported, err = q.CreateHolidays(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := utils.ConvertDBHolidayCalendarToModelsHolidayCalendar(calendarRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting holiday calendar: %v", err)
+	}
+
+	return &models.HolidayCalendarImport{Calendar: *calendar, Imported: int(imported)}, nil
+}
+
+func (hs *HolidayService) GetHolidayCalendars(ctx context.Context) ([]models.HolidayCalendar, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin, models.RoleManager, models.RoleEmployee)
+	if err != nil {
+		return nil, err
+	}
+
+	calendarsRaw, err := hs.repository.GetHolidayCalendars(ctx, pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]models.HolidayCalendar, len(calendarsRaw))
+	for i, calendarRaw := range calendarsRaw {
+		calendar, err := utils.ConvertDBHolidayCalendarToModelsHolidayCalendar(calendarRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting holiday calendar: %v", err)
+		}
+		calendars[i] = *calendar
+	}
+
+	return calendars, nil
+}
+
+func (hs *HolidayService) GetHolidays(ctx context.Context, calendarUUID uuid.UUID, year int) ([]models.Holiday, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin, models.RoleManager, models.RoleEmployee)
+	if err != nil {
+		return nil, err
+	}
+
+	calendarPgUUID := pgtype.UUID{Bytes: calendarUUID, Valid: true}
+
+	if _, err := hs.repository.GetHolidayCalendarByUUID(ctx, db.GetHolidayCalendarByUUIDParams{
+		CalendarUuid:     calendarPgUUID,
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrHolidayCalendarNotFound
+		}
+		return nil, err
+	}
+
+	from, to := yearBounds(year)
+	holidaysRaw, err := hs.repository.GetHolidaysInRange(ctx, db.GetHolidaysInRangeParams{
+		CalendarUuid: calendarPgUUID,
+		FromDate:     pgtype.Date{Time: from, Valid: true},
+		ToDate:       pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Holiday, len(holidaysRaw))
+	for i, holidayRaw := range holidaysRaw {
+		result[i] = utils.ConvertDBHolidayToModelsHoliday(holidayRaw)
+	}
+
+	return result, nil
+}
+
+func (hs *HolidayService) DeleteHolidayCalendar(ctx context.Context, calendarUUID uuid.UUID) error {
+	principal, err := requireRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := hs.repository.DeleteHolidayCalendar(ctx, db.DeleteHolidayCalendarParams{
+		CalendarUuid:     pgtype.UUID{Bytes: calendarUUID, Valid: true},
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrHolidayCalendarNotFound
+	}
+
+	return nil
+}
+
+// SetUserHolidayCalendar makes the calendar the source of public holidays for
+// the user, replacing any previous one.
+func (hs *HolidayService) SetUserHolidayCalendar(ctx context.Context, userUUID, calendarUUID uuid.UUID) error {
+	if err := authorizeUser(ctx, hs.repository, userUUID, accessManage); err != nil {
+		return err
+	}
+	principal, _ := PrincipalFromContext(ctx)
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+	calendarPgUUID := pgtype.UUID{Bytes: calendarUUID, Valid: true}
+
+	return hs.repository.ExecTx(ctx, func(q db.Querier) error {
+		if _, err := q.GetUserByUUID(ctx, userPgUUID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		// Only calendars of the organization of the user can be assigned.
+		if _, err := q.GetHolidayCalendarByUUID(ctx, db.GetHolidayCalendarByUUIDParams{
+			CalendarUuid:     calendarPgUUID,
+			OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrHolidayCalendarNotFound
+			}
+			return err
+		}
+
+		return q.SetUserHolidayCalendar(ctx, db.SetUserHolidayCalendarParams{
+			UserUuid:     userPgUUID,
+			CalendarUuid: calendarPgUUID,
+		})
+	})
+}
+
+func (hs *HolidayService) DeleteUserHolidayCalendar(ctx context.Context, userUUID uuid.UUID) error {
+	if err := authorizeUser(ctx, hs.repository, userUUID, accessManage); err != nil {
+		return err
+	}
+
+	deleted, err := hs.repository.DeleteUserHolidayCalendar(ctx, pgtype.UUID{Bytes: userUUID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrHolidayCalendarNotFound
+	}
+
+	return nil
+}
+
+// SetTeamHolidayCalendar makes the calendar the source of public holidays for
+// the team of the manager, the users whose manager they are and who have no
+// calendar of their own.
+func (hs *HolidayService) SetTeamHolidayCalendar(ctx context.Context, managerUUID, calendarUUID uuid.UUID) error {
+	if err := authorizeTeam(ctx, hs.repository, managerUUID); err != nil {
+		return err
+	}
+	principal, _ := PrincipalFromContext(ctx)
+
+	managerPgUUID := pgtype.UUID{Bytes: managerUUID, Valid: true}
+	calendarPgUUID := pgtype.UUID{Bytes: calendarUUID, Valid: true}
+
+	return hs.repository.ExecTx(ctx, func(q db.Querier) error {
+		if _, err := q.GetUserByUUID(ctx, managerPgUUID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if _, err := q.GetHolidayCalendarByUUID(ctx, db.GetHolidayCalendarByUUIDParams{
+			CalendarUuid:     calendarPgUUID,
+			OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrHolidayCalendarNotFound
+			}
+			return err
+		}
+
+		return q.SetTeamHolidayCalendar(ctx, db.SetTeamHolidayCalendarParams{
+			ManagerUuid:  managerPgUUID,
+			CalendarUuid: calendarPgUUID,
+		})
+	})
+}
+
+func (hs *HolidayService) DeleteTeamHolidayCalendar(ctx context.Context, managerUUID uuid.UUID) error {
+	if err := authorizeTeam(ctx, hs.repository, managerUUID); err != nil {
+		return err
+	}
+
+	deleted, err := hs.repository.DeleteTeamHolidayCalendar(ctx, pgtype.UUID{Bytes: managerUUID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrHolidayCalendarNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key reused with a different request")
+)
+
+type IdempotencyService struct {
+	repository db.Querier
+	system     db.Querier // Sees every organization, for the key cleanup
+	ttl        time.Duration
+}
+
+func NewIdempotencyService(repository, system db.Querier, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repository: repository,
+		system:     system,
+		ttl:        ttl,
+	}
+}
+
+// ReserveIdempotencyKey claims the key for a request with the given hash. It
+// returns the recorded response when the key was already used for the same
+// request, and nil when the caller should handle the request and save its
+// response.
+func (is *IdempotencyService) ReserveIdempotencyKey(ctx context.Context, userUUID uuid.UUID, key, requestHash string) (*models.IdempotentResponse, error) {
+	// Keys are scoped to the user whose timers the request writes, so only
+	// callers allowed to write them may reserve or replay one.
+	if err := authorizeUser(ctx, is.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := is.repository.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+		UserUuid:    userPgUUID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(is.ttl), Valid: true},
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+		return nil, ErrUserNotFound
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// The key is held by a live entry.
+	keyRaw, err := is.repository.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		UserUuid: userPgUUID,
+		Key:      key,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released by a failed request in the meantime.
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, err
+	}
+
+	if keyRaw.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !keyRaw.StatusCode.Valid {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &models.IdempotentResponse{
+		StatusCode: int(keyRaw.StatusCode.Int32),
+		Body:       keyRaw.ResponseBody,
+	}, nil
+}
+
+func (is *IdempotencyService) SaveIdempotentResponse(ctx context.Context, userUUID uuid.UUID, key string, response *models.IdempotentResponse) error {
+	return is.repository.SaveIdempotencyResponse(ctx, db.SaveIdempotencyResponseParams{
+		StatusCode:   pgtype.Int4{Int32: int32(response.StatusCode), Valid: true},
+		ResponseBody: response.Body,
+		UserUuid:     pgtype.UUID{Bytes: userUUID, Valid: true},
+		Key:          key,
+	})
+}
+
+// ReleaseIdempotencyKey forgets a key whose request failed, so that a retry
+// is handled again.
+func (is *IdempotencyService) ReleaseIdempotencyKey(ctx context.Context, userUUID uuid.UUID, key string) error {
+	return is.repository.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		UserUuid: pgtype.UUID{Bytes: userUUID, Valid: true},
+		Key:      key,
+	})
+}
+
+func (is *IdempotencyService) PurgeExpiredIdempotencyKeys(ctx context.Context) error {
+	deleted, err := is.system.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logrus.Infof("Purged %d expired idempotency keys", deleted)
+	}
+
+	return nil
+}
+
+// WatchIdempotencyKeys calls PurgeExpiredIdempotencyKeys every interval until ctx is done.
+func (is *IdempotencyService) WatchIdempotencyKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := is.PurgeExpiredIdempotencyKeys(ctx); err != nil {
+				logrus.Errorf("Error purging expired idempotency keys: %v", err)
+			}
+		}
+	}
+}
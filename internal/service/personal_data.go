@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// anonymizedPlaceholder replaces the required personal data of anonymized
+// users.
+const anonymizedPlaceholder = "anonymized"
+
+// anonymizedAuditMembers are the members of the user snapshots in the audit
+// log removed by anonymization.
+var anonymizedAuditMembers = []string{"passportNumber", "surname", "name", "patronymic", "address", "email"}
+
+// ExportUserData returns everything stored about a user, read in a single
+// snapshot. Only admins may export the data of a user.
+func (ps *UserService) ExportUserData(ctx context.Context, UUID uuid.UUID) (*models.UserExport, error) {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
+
+	var export *models.UserExport
+	err := ps.repository.ExecTxWithIsolation(ctx, pgx.RepeatableRead, func(q db.Querier) error {
+		var err error
+		export, err = ps.exportUserData(ctx, q, pgUUID)
+		if err != nil {
+			return err
+		}
+
+		return revealPassportNumbers(ctx, q, "ExportUserData", &export.Profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (ps *UserService) exportUserData(ctx context.Context, q db.Querier, pgUUID pgtype.UUID) (*models.UserExport, error) {
+	userRaw, err := q.GetUserByUUID(ctx, pgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	user, err := ps.convertUser(userRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting user: %v", err)
+	}
+
+	export := &models.UserExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    *user,
+	}
+
+	taskRaw, err := q.GetActiveTask(ctx, pgUUID)
+	switch {
+	case err == nil:
+		export.ActiveTask, err = utils.ConvertDBTaskToModelsTask(taskRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting task: %v", err)
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	historyRaw, err := q.GetTaskHistoriesByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.History = make([]models.TaskHistory, len(historyRaw))
+	for i, entryRaw := range historyRaw {
+		entry, err := utils.ConvertDBTaskHistoryEntryToModelsTaskHistory(db.GetTaskHistoryByUUIDRow(entryRaw))
+		if err != nil {
+			return nil, fmt.Errorf("error converting task history entry: %v", err)
+		}
+		export.History[i] = *entry
+	}
+
+	settingsRaw, err := q.GetPomodoroSettings(ctx, pgUUID)
+	switch {
+	case err == nil:
+		export.PomodoroSettings = utils.ConvertDBPomodoroSettingToModelsPomodoroSettings(settingsRaw)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	schedulesRaw, err := q.GetWorkSchedules(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.WorkSchedules = make([]models.WorkSchedule, len(schedulesRaw))
+	for i, scheduleRaw := range schedulesRaw {
+		schedule, err := utils.ConvertDBWorkScheduleToModelsWorkSchedule(scheduleRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting work schedule: %v", err)
+		}
+		export.WorkSchedules[i] = *schedule
+	}
+
+	absencesRaw, err := q.GetAbsencesByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.Absences = make([]models.Absence, len(absencesRaw))
+	for i, absenceRaw := range absencesRaw {
+		absence, err := utils.ConvertDBAbsenceToModelsAbsence(absenceRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting absence: %v", err)
+		}
+		export.Absences[i] = *absence
+	}
+
+	balancesRaw, err := q.GetAbsenceBalancesByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.AbsenceAllowances = make([]models.AbsenceBalancePayload, len(balancesRaw))
+	for i, balanceRaw := range balancesRaw {
+		export.AbsenceAllowances[i] = models.AbsenceBalancePayload{
+			Year:          int(balanceRaw.Year),
+			Type:          balanceRaw.Type,
+			AllowanceDays: balanceRaw.AllowanceDays,
+		}
+	}
+
+	budgetsRaw, err := q.GetTaskBudgetsUsage(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.TaskBudgets = make([]models.TaskBudget, len(budgetsRaw))
+	for i, budgetRaw := range budgetsRaw {
+		budget, err := utils.ConvertDBTaskBudgetUsageToModelsTaskBudget(budgetRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting task budget: %v", err)
+		}
+		export.TaskBudgets[i] = *budget
+	}
+
+	calendarUUID, err := q.GetUserHolidayCalendar(ctx, pgUUID)
+	switch {
+	case err == nil:
+		export.HolidayCalendarUUID = utils.FromPgUUID(calendarUUID)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	identitiesRaw, err := q.GetUserIdentities(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.Identities = make([]models.UserIdentity, len(identitiesRaw))
+	for i, identityRaw := range identitiesRaw {
+		export.Identities[i] = utils.ConvertDBUserIdentityToModelsUserIdentity(identityRaw)
+	}
+
+	keysRaw, err := q.GetAPIKeysByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.APIKeys = make([]models.APIKey, len(keysRaw))
+	for i, keyRaw := range keysRaw {
+		key, err := utils.ConvertDBAPIKeyToModelsAPIKey(keyRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting API key: %v", err)
+		}
+		export.APIKeys[i] = *key
+	}
+
+	entriesRaw, err := q.GetAuditEntriesByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.AuditEntries = make([]models.AuditEntry, len(entriesRaw))
+	for i, entryRaw := range entriesRaw {
+		export.AuditEntries[i] = utils.ConvertDBAuditLogToModelsAuditEntry(entryRaw)
+	}
+
+	accessesRaw, err := q.GetPIIAccessesByUser(ctx, pgUUID)
+	if err != nil {
+		return nil, err
+	}
+	export.PIIAccesses = make([]models.PIIAccess, len(accessesRaw))
+	for i, accessRaw := range accessesRaw {
+		export.PIIAccesses[i] = utils.ConvertDBPIIAccessToModelsPIIAccess(accessRaw)
+	}
+
+	return export, nil
+}
+
+// AnonymizeUserByUUID removes the personal data of a user for good and
+// deactivates them. The tracked time, absences and settings are kept for
+// reports. Linked identities are unlinked, API keys revoked, and the personal
+// data recorded in the audit log is removed too.
+func (ps *UserService) AnonymizeUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error) {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
+
+	var userRaw db.User
+	err := ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		userRaw, err = q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if userRaw.AnonymizedAt.Valid {
+			return nil
+		}
+		before := userRaw
+
+		userRaw, err = q.AnonymizeUserByUUID(ctx, db.AnonymizeUserByUUIDParams{
+			Placeholder: anonymizedPlaceholder,
+			UserUuid:    pgUUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteUserIdentities(ctx, pgUUID); err != nil {
+			return err
+		}
+		if err := q.RevokeAPIKeysByUser(ctx, pgUUID); err != nil {
+			return err
+		}
+
+		// The entry is redacted with the earlier ones, so it only keeps the
+		// members that are not personal data.
+		if err := ps.auditUser(ctx, q, models.AuditActionAnonymize, &before, &userRaw); err != nil {
+			return err
+		}
+		if err := q.EnableAuditRedaction(ctx); err != nil {
+			return err
+		}
+		return q.RedactUserAuditEntries(ctx, db.RedactUserAuditEntriesParams{
+			Fields:   anonymizedAuditMembers,
+			UserUuid: pgUUID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ps.convertUser(userRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting user: %v", err)
+	}
+
+	return user, nil
+}
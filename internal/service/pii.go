@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"slices"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// piiFieldPassportNumber names the passport number in the PII access log.
+const piiFieldPassportNumber = "passport_number"
+
+// passportVisibleDigits is the number of digits left unmasked at each end of
+// a passport number.
+const passportVisibleDigits = 2
+
+type accessReasonKey struct{}
+
+// WithAccessReason returns a copy of ctx carrying the reason given by the
+// caller for reading personal data, recorded in the PII access log.
+func WithAccessReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, accessReasonKey{}, reason)
+}
+
+func accessReasonFromContext(ctx context.Context) *string {
+	reason, ok := ctx.Value(accessReasonKey{}).(string)
+	if !ok || reason == "" {
+		return nil
+	}
+	return &reason
+}
+
+// canReadPII reports whether principal sees personal data in full.
+func canReadPII(principal *models.Principal) bool {
+	return principal.Role == models.RoleAdmin || slices.Contains(principal.Permissions, models.PermissionPIIRead)
+}
+
+// revealPassportNumbers masks the passport numbers of users for callers who may
+// not read them in full, and records the full reads of the others in the PII
+// access log. operation names the service method returning the users.
+func revealPassportNumbers(ctx context.Context, repository db.Querier, operation string, users ...*models.User) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !canReadPII(principal) {
+		for _, user := range users {
+			user.PassportNumber = maskPassportNumber(user.PassportNumber)
+		}
+		return nil
+	}
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	userUUIDs := make([]pgtype.UUID, len(users))
+	for i, user := range users {
+		userUUIDs[i] = pgtype.UUID{Bytes: user.UUID, Valid: true}
+	}
+
+	params := db.CreatePIIAccessesParams{
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		Actor:            principal.Subject,
+		UserUuids:        userUUIDs,
+		Field:            piiFieldPassportNumber,
+		Operation:        operation,
+		Reason:           utils.ToPgText(accessReasonFromContext(ctx)),
+	}
+	if principal.UserUUID != nil {
+		params.ActorUserUuid = pgtype.UUID{Bytes: *principal.UserUUID, Valid: true}
+	}
+	if principal.APIKeyUUID != nil {
+		params.ApiKeyUuid = pgtype.UUID{Bytes: *principal.APIKeyUUID, Valid: true}
+	}
+
+	return repository.CreatePIIAccesses(ctx, params)
+}
+
+// maskPassportNumber hides the digits of a passport number but the first and
+// last ones: "8234 567891" becomes "82** ****91".
+func maskPassportNumber(passportNumber string) string {
+	digits := 0
+	for _, r := range passportNumber {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	masked := []rune(passportNumber)
+	position := 0
+	for i, r := range masked {
+		if !unicode.IsDigit(r) {
+			continue
+		}
+		if position >= passportVisibleDigits && position < digits-passportVisibleDigits {
+			masked[i] = '*'
+		}
+		position++
+	}
+	return string(masked)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// access is the kind of operation performed on the data of a user.
+type access int
+
+const (
+	// accessRead covers reports and settings: the user, their manager and
+	// admins.
+	accessRead access = iota
+	// accessWrite covers timers and settings: the user and admins. Identity
+	// data is changed by admins only, with requireRole.
+	accessWrite
+	// accessManage covers what is decided for a user, such as allowances and
+	// calendars: their manager and admins.
+	accessManage
+)
+
+// The policy is enforced by the service methods themselves so it holds for
+// every entry point. Background jobs that act on all users do not go through
+// it.
+
+// requireRole returns the calling principal when it has one of roles.
+func requireRole(ctx context.Context, roles ...string) (*models.Principal, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if !slices.Contains(roles, principal.Role) {
+		return nil, ErrForbidden
+	}
+	return principal, nil
+}
+
+// authorizeUser checks that the calling principal may perform access on the
+// data of the user userUUID. Users of other organizations are never
+// accessible. A missing user is left to the caller to report.
+func authorizeUser(ctx context.Context, repository db.Querier, userUUID uuid.UUID, access access) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	userRaw, err := repository.GetUserByUUID(ctx, pgtype.UUID{Bytes: userUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if uuid.UUID(userRaw.OrganizationUuid.Bytes) != principal.OrganizationUUID {
+		return ErrForbidden
+	}
+	// Deactivated users are read-only until they are restored.
+	if userRaw.DeletedAt.Valid && access == accessWrite {
+		return ErrForbidden
+	}
+	if principal.Role == models.RoleAdmin {
+		return nil
+	}
+
+	self := principal.UserUUID != nil && *principal.UserUUID == userUUID
+	if self && access != accessManage {
+		return nil
+	}
+	if access == accessWrite || principal.Role != models.RoleManager || principal.UserUUID == nil {
+		return ErrForbidden
+	}
+
+	if !userRaw.ManagerUuid.Valid || uuid.UUID(userRaw.ManagerUuid.Bytes) != *principal.UserUUID {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeTeam checks that the calling principal may read the data of the
+// team of the manager managerUUID, the users whose manager they are: the
+// manager themselves and admins. A missing user is left to the caller to
+// report.
+func authorizeTeam(ctx context.Context, repository db.Querier, managerUUID uuid.UUID) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	userRaw, err := repository.GetUserByUUID(ctx, pgtype.UUID{Bytes: managerUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if uuid.UUID(userRaw.OrganizationUuid.Bytes) != principal.OrganizationUUID {
+		return ErrForbidden
+	}
+	if principal.Role == models.RoleAdmin {
+		return nil
+	}
+	if principal.Role != models.RoleManager || principal.UserUUID == nil || *principal.UserUUID != managerUUID {
+		return ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	shortBreakName = "Short break"
+	longBreakName  = "Long break"
+)
+
+type PomodoroService struct {
+	repository db.Store
+	system     db.Store // Sees every organization, for the interval watcher
+}
+
+func NewPomodoroService(repository, system db.Store) *PomodoroService {
+	return &PomodoroService{
+		repository: repository,
+		system:     system,
+	}
+}
+
+func (ps *PomodoroService) GetPomodoroSettings(ctx context.Context, userUUID uuid.UUID) (*models.PomodoroSettings, error) {
+	if err := authorizeUser(ctx, ps.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	settingsRaw, err := ps.repository.GetOrCreatePomodoroSettings(ctx, pgtype.UUID{Bytes: userUUID, Valid: true})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return utils.ConvertDBPomodoroSettingToModelsPomodoroSettings(settingsRaw), nil
+}
+
+func (ps *PomodoroService) UpdatePomodoroSettings(ctx context.Context, userUUID uuid.UUID, payload *models.UpdatePomodoroSettingsPayload) (*models.PomodoroSettings, error) {
+	if err := authorizeUser(ctx, ps.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	params := db.UpdatePomodoroSettingsParams{
+		UserUuid:              userPgUUID,
+		FocusMinutes:          utils.ToPgInt4(payload.FocusMinutes),
+		ShortBreakMinutes:     utils.ToPgInt4(payload.ShortBreakMinutes),
+		LongBreakMinutes:      utils.ToPgInt4(payload.LongBreakMinutes),
+		CyclesBeforeLongBreak: utils.ToPgInt4(payload.CyclesBeforeLongBreak),
+		AutoStartBreak:        utils.ToPgBool(payload.AutoStartBreak),
+	}
+
+	var settingsRaw db.PomodoroSetting
+	err := ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		if _, err := q.GetOrCreatePomodoroSettings(ctx, userPgUUID); err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var err error
+		settingsRaw, err = q.UpdatePomodoroSettings(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ConvertDBPomodoroSettingToModelsPomodoroSettings(settingsRaw), nil
+}
+
+// FinishExpiredIntervals stops every focus or break interval whose planned end
+// has passed, records finished focus intervals and starts the following break.
+func (ps *PomodoroService) FinishExpiredIntervals(ctx context.Context) error {
+	var tasksRaw []db.Task
+	err := ps.system.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		tasksRaw, err = q.DeleteExpiredTasks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, taskRaw := range tasksRaw {
+			if err := auditTask(ctx, q, &taskRaw, nil); err != nil {
+				return err
+			}
+			if taskRaw.Mode == models.TaskModeBreak {
+				continue
+			}
+
+			params := db.CreateTaskHistoryParams{
+				UserUuid:    taskRaw.UserUuid,
+				Name:        taskRaw.Name,
+				Description: taskRaw.Description,
+				Notes:       taskRaw.Notes,
+				StartTime:   taskRaw.StartTime,
+				EndTime:     taskRaw.PlannedEndTime,
+				Mode:        taskRaw.Mode,
+			}
+			entryRaw, err := q.CreateTaskHistory(ctx, params)
+			if err != nil {
+				return fmt.Errorf("error recording interval %q: %w", taskRaw.Name, err)
+			}
+			if err := auditTaskHistory(ctx, q, nil, createdTaskHistoryEntry(entryRaw)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Breaks are started after the commit: a user who already started
+	// another task makes CreateTask fail, which would abort the transaction.
+	// A failed break is logged so the other users still get theirs.
+	for _, taskRaw := range tasksRaw {
+		if taskRaw.Mode == models.TaskModeFocus {
+			if err := ps.startBreak(ctx, taskRaw.UserUuid); err != nil {
+				logrus.Errorf("Error starting the break of user %s: %v", utils.FromPgUUID(taskRaw.UserUuid), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WatchPomodoros calls FinishExpiredIntervals every interval until ctx is done.
+func (ps *PomodoroService) WatchPomodoros(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ps.FinishExpiredIntervals(ctx); err != nil {
+				logrus.Errorf("Error finishing expired pomodoro intervals: %v", err)
+			}
+		}
+	}
+}
+
+func (ps *PomodoroService) startBreak(ctx context.Context, userPgUUID pgtype.UUID) error {
+	settingsRaw, err := ps.system.GetOrCreatePomodoroSettings(ctx, userPgUUID)
+	if err != nil {
+		return err
+	}
+
+	if !settingsRaw.AutoStartBreak {
+		return nil
+	}
+
+	completed, err := ps.system.CountCompletedPomodorosToday(ctx, userPgUUID)
+	if err != nil {
+		return err
+	}
+
+	params := db.CreateTaskParams{
+		UserUuid:       userPgUUID,
+		Name:           shortBreakName,
+		Mode:           models.TaskModeBreak,
+		PlannedMinutes: pgtype.Int4{Int32: settingsRaw.ShortBreakMinutes, Valid: true},
+	}
+	if completed%int64(settingsRaw.CyclesBeforeLongBreak) == 0 {
+		params.Name = longBreakName
+		params.PlannedMinutes = pgtype.Int4{Int32: settingsRaw.LongBreakMinutes, Valid: true}
+	}
+
+	err = ps.system.ExecTx(ctx, func(q db.Querier) error {
+		taskRaw, err := q.CreateTask(ctx, params)
+		if err != nil {
+			return err
+		}
+		return auditTask(ctx, q, nil, &taskRaw)
+	})
+	if err != nil {
+		// The user already started something else in the meantime.
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// pomodoroStore holds the expired intervals of several users for the interval
+// watcher.
+type pomodoroStore struct {
+	fakeStore
+
+	expired   []db.Task
+	completed map[pgtype.UUID]int64
+	failing   map[pgtype.UUID]bool // Users whose break cannot be started
+
+	breaks map[pgtype.UUID]db.CreateTaskParams
+}
+
+func (p *pomodoroStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(p)
+}
+
+func (p *pomodoroStore) DeleteExpiredTasks(ctx context.Context) ([]db.Task, error) {
+	expired := p.expired
+	p.expired = nil
+	return expired, nil
+}
+
+func (p *pomodoroStore) GetOrCreatePomodoroSettings(ctx context.Context, userUuid pgtype.UUID) (db.PomodoroSetting, error) {
+	return db.PomodoroSetting{
+		UserUuid:              userUuid,
+		FocusMinutes:          25,
+		ShortBreakMinutes:     5,
+		LongBreakMinutes:      15,
+		CyclesBeforeLongBreak: 4,
+		AutoStartBreak:        true,
+	}, nil
+}
+
+func (p *pomodoroStore) CountCompletedPomodorosToday(ctx context.Context, userUuid pgtype.UUID) (int64, error) {
+	return p.completed[userUuid], nil
+}
+
+func (p *pomodoroStore) CreateTask(ctx context.Context, arg db.CreateTaskParams) (db.Task, error) {
+	if p.failing[arg.UserUuid] {
+		return db.Task{}, errors.New("connection reset")
+	}
+	p.breaks[arg.UserUuid] = arg
+	return db.Task{Uuid: pgUUID(uuid.New()), UserUuid: arg.UserUuid, Name: arg.Name, Mode: arg.Mode}, nil
+}
+
+func TestFinishExpiredIntervals(t *testing.T) {
+	now := time.Now().UTC()
+	focus := func(userUUID pgtype.UUID) db.Task {
+		return db.Task{
+			Uuid:           pgUUID(uuid.New()),
+			UserUuid:       userUUID,
+			Name:           "coding",
+			Mode:           models.TaskModeFocus,
+			StartTime:      pgTime(now.Add(-30 * time.Minute)),
+			PlannedEndTime: pgTime(now.Add(-5 * time.Minute)),
+		}
+	}
+
+	failing, short, long, resting := pgUUID(uuid.New()), pgUUID(uuid.New()), pgUUID(uuid.New()), pgUUID(uuid.New())
+	store := &pomodoroStore{
+		expired: []db.Task{
+			focus(failing),
+			focus(short),
+			focus(long),
+			{UserUuid: resting, Name: shortBreakName, Mode: models.TaskModeBreak, PlannedEndTime: pgTime(now)},
+		},
+		completed: map[pgtype.UUID]int64{failing: 1, short: 3, long: 4},
+		failing:   map[pgtype.UUID]bool{failing: true},
+		breaks:    map[pgtype.UUID]db.CreateTaskParams{},
+	}
+	ps := NewPomodoroService(store, store)
+
+	if err := ps.FinishExpiredIntervals(context.Background()); err != nil {
+		t.Fatalf("FinishExpiredIntervals() error = %v", err)
+	}
+
+	if len(store.history) != 3 {
+		t.Fatalf("history has %d entries, want the 3 focus intervals", len(store.history))
+	}
+	for _, entry := range store.history {
+		if entry.Mode != models.TaskModeFocus || !entry.EndTime.Time.Equal(now.Add(-5*time.Minute)) {
+			t.Fatalf("history entry = %+v, want a focus interval ending at its planned end", entry)
+		}
+	}
+
+	// The failed break of the first user does not keep the others from
+	// theirs.
+	if len(store.breaks) != 2 {
+		t.Fatalf("started %d breaks, want 2", len(store.breaks))
+	}
+	if got := store.breaks[short]; got.Name != shortBreakName || got.PlannedMinutes.Int32 != 5 {
+		t.Fatalf("break after the 3rd pomodoro = %s of %d minutes", got.Name, got.PlannedMinutes.Int32)
+	}
+	if got := store.breaks[long]; got.Name != longBreakName || got.PlannedMinutes.Int32 != 15 {
+		t.Fatalf("break after the 4th pomodoro = %s of %d minutes", got.Name, got.PlannedMinutes.Int32)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+type ScheduleService struct {
+	repository db.Querier
+}
+
+func NewScheduleService(repository db.Querier) *ScheduleService {
+	return &ScheduleService{
+		repository: repository,
+	}
+}
+
+func (ss *ScheduleService) SetWorkSchedule(ctx context.Context, userUUID uuid.UUID, effectiveFrom time.Time, payload *models.WorkSchedulePayload) (*models.WorkSchedule, error) {
+	if err := authorizeUser(ctx, ss.repository, userUUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	params := db.UpsertWorkScheduleParams{
+		UserUuid:         pgtype.UUID{Bytes: userUUID, Valid: true},
+		EffectiveFrom:    pgtype.Date{Time: effectiveFrom, Valid: true},
+		MondayMinutes:    hoursToMinutes(payload.Monday),
+		TuesdayMinutes:   hoursToMinutes(payload.Tuesday),
+		WednesdayMinutes: hoursToMinutes(payload.Wednesday),
+		ThursdayMinutes:  hoursToMinutes(payload.Thursday),
+		FridayMinutes:    hoursToMinutes(payload.Friday),
+		SaturdayMinutes:  hoursToMinutes(payload.Saturday),
+		SundayMinutes:    hoursToMinutes(payload.Sunday),
+	}
+
+	scheduleRaw, err := ss.repository.UpsertWorkSchedule(ctx, params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	schedule, err := utils.ConvertDBWorkScheduleToModelsWorkSchedule(scheduleRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+func (ss *ScheduleService) GetWorkSchedules(ctx context.Context, userUUID uuid.UUID) ([]models.WorkSchedule, error) {
+	if err := authorizeUser(ctx, ss.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := ss.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	schedulesRaw, err := ss.repository.GetWorkSchedules(ctx, userPgUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]models.WorkSchedule, len(schedulesRaw))
+	for i, scheduleRaw := range schedulesRaw {
+		schedule, err := utils.ConvertDBWorkScheduleToModelsWorkSchedule(scheduleRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting schedule: %v", err)
+		}
+		schedules[i] = *schedule
+	}
+
+	return schedules, nil
+}
+
+func (ss *ScheduleService) DeleteWorkSchedule(ctx context.Context, userUUID, scheduleUUID uuid.UUID) error {
+	if err := authorizeUser(ctx, ss.repository, userUUID, accessManage); err != nil {
+		return err
+	}
+
+	params := db.DeleteWorkScheduleParams{
+		ScheduleUuid: pgtype.UUID{Bytes: scheduleUUID, Valid: true},
+		UserUuid:     pgtype.UUID{Bytes: userUUID, Valid: true},
+	}
+
+	deleted, err := ss.repository.DeleteWorkSchedule(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+// GetOvertimeReport compares the tracked time against the contracted schedule
+// for every day between from and to, both inclusive, grouped by groupBy.
+func (ss *ScheduleService) GetOvertimeReport(ctx context.Context, userUUID uuid.UUID, from, to time.Time, groupBy string) (*models.OvertimeReport, error) {
+	if err := authorizeUser(ctx, ss.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := ss.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	calendar, err := newWorkCalendar(ctx, ss.repository, userPgUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trackedRows, err := ss.repository.GetDailyTrackedSeconds(ctx, db.GetDailyTrackedSecondsParams{
+		UserUuid: userPgUUID,
+		FromTime: pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]int64, len(trackedRows))
+	for _, row := range trackedRows {
+		tracked[row.Day.Time.Format(time.DateOnly)] = row.TrackedSeconds
+	}
+
+	var periods []overtimeTotals
+	var total overtimeTotals
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		start, end := periodBounds(day, groupBy)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		if len(periods) == 0 || !periods[len(periods)-1].start.Equal(start) {
+			periods = append(periods, overtimeTotals{start: start, end: end})
+		}
+
+		expected := int64(calendar.expectedMinutes(day)) * 60
+		absent := int64(calendar.absentMinutes(day)) * 60
+		actual := tracked[day.Format(time.DateOnly)]
+		periods[len(periods)-1].add(expected, actual, absent)
+		total.add(expected, actual, absent)
+	}
+
+	report := &models.OvertimeReport{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		GroupBy: groupBy,
+		Periods: make([]models.OvertimePeriod, len(periods)),
+	}
+	report.Expected, report.Actual, report.Absent, report.Overtime, report.Undertime = total.format()
+	for i, period := range periods {
+		p := models.OvertimePeriod{
+			Start: period.start.Format(time.DateOnly),
+			End:   period.end.Format(time.DateOnly),
+		}
+		p.Expected, p.Actual, p.Absent, p.Overtime, p.Undertime = period.format()
+		report.Periods[i] = p
+	}
+
+	return report, nil
+}
+
+type overtimeTotals struct {
+	start, end               time.Time
+	expected, actual, absent int64
+}
+
+func (ot *overtimeTotals) add(expected, actual, absent int64) {
+	ot.expected += expected
+	ot.actual += actual
+	ot.absent += absent
+}
+
+func (ot *overtimeTotals) format() (expected, actual, absent, overtime, undertime string) {
+	balance := ot.actual - ot.expected
+	return utils.FormatDuration(ot.expected),
+		utils.FormatDuration(ot.actual),
+		utils.FormatDuration(ot.absent),
+		utils.FormatDuration(max(balance, 0)),
+		utils.FormatDuration(max(-balance, 0))
+}
+
+// periodBounds returns the first and last day of the day, ISO week or month
+// that contains day.
+func periodBounds(day time.Time, groupBy string) (time.Time, time.Time) {
+	switch groupBy {
+	case models.GroupByWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 6)
+	case models.GroupByMonth:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, -1)
+	default:
+		return day, day
+	}
+}
+
+func hoursToMinutes(hours float64) int32 {
+	return int32(hours*60 + 0.5)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"testing"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func formatHours(h int) string {
+	return utils.FormatDuration(int64(h) * 3600)
+}
+
+// overtimeStore is a full-time user, 8 hours Monday to Friday, with a holiday
+// on Wednesday 2024-05-01 and half of Friday 2024-05-03 off.
+func overtimeStore() *fakeStore {
+	tracked := map[pgtype.Date]int{
+		pgDate(2024, time.April, 24): 9,
+		pgDate(2024, time.April, 25): 8,
+		pgDate(2024, time.April, 26): 8,
+		pgDate(2024, time.April, 27): 2,
+		pgDate(2024, time.April, 29): 8,
+		pgDate(2024, time.April, 30): 7,
+		pgDate(2024, time.May, 2):    8,
+		pgDate(2024, time.May, 3):    4,
+		pgDate(2024, time.May, 6):    8,
+		pgDate(2024, time.May, 7):    8,
+	}
+
+	store := &fakeStore{
+		schedules: []db.WorkSchedule{{
+			EffectiveFrom:    pgDate(2024, time.January, 1),
+			MondayMinutes:    480,
+			TuesdayMinutes:   480,
+			WednesdayMinutes: 480,
+			ThursdayMinutes:  480,
+			FridayMinutes:    480,
+		}},
+		absences: []db.Absence{{
+			Type:         "vacation",
+			StartDate:    pgDate(2024, time.May, 3),
+			EndDate:      pgDate(2024, time.May, 3),
+			HalfDayStart: true,
+		}},
+		holidays: []db.Holiday{{Day: pgDate(2024, time.May, 1), Name: "Labour Day"}},
+	}
+	for day, h := range tracked {
+		store.tracked = append(store.tracked, db.GetDailyTrackedSecondsRow{
+			Day:            day,
+			TrackedSeconds: int64(h) * 3600,
+		})
+	}
+	return store
+}
+
+func TestGetOvertimeReport(t *testing.T) {
+	from := time.Date(2024, time.April, 24, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.May, 7, 0, 0, 0, 0, time.UTC)
+
+	// period is start, end, expected, actual, absent, overtime and undertime,
+	// the durations in hours.
+	type period struct {
+		start, end                                    string
+		expected, actual, absent, overtime, undertime int
+	}
+
+	tests := []struct {
+		groupBy string
+		want    []period
+	}{
+		{
+			groupBy: models.GroupByWeek,
+			want: []period{
+				{"2024-04-24", "2024-04-28", 24, 27, 0, 3, 0},
+				{"2024-04-29", "2024-05-05", 28, 27, 4, 0, 1},
+				{"2024-05-06", "2024-05-07", 16, 16, 0, 0, 0},
+			},
+		},
+		{
+			groupBy: models.GroupByMonth,
+			want: []period{
+				{"2024-04-24", "2024-04-30", 40, 42, 0, 2, 0},
+				{"2024-05-01", "2024-05-07", 28, 28, 4, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			ss := NewScheduleService(overtimeStore())
+
+			report, err := ss.GetOvertimeReport(userContext(), testUserUUID, from, to, tt.groupBy)
+			if err != nil {
+				t.Fatalf("GetOvertimeReport() error = %v", err)
+			}
+
+			if report.Expected != formatHours(68) || report.Actual != formatHours(70) || report.Absent != formatHours(4) ||
+				report.Overtime != formatHours(2) || report.Undertime != formatHours(0) {
+				t.Fatalf("GetOvertimeReport() totals = %s expected, %s actual, %s absent, %s overtime, %s undertime",
+					report.Expected, report.Actual, report.Absent, report.Overtime, report.Undertime)
+			}
+
+			if len(report.Periods) != len(tt.want) {
+				t.Fatalf("GetOvertimeReport() returned %d periods, want %d", len(report.Periods), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := report.Periods[i]
+				wantPeriod := models.OvertimePeriod{
+					Start:     want.start,
+					End:       want.end,
+					Expected:  formatHours(want.expected),
+					Actual:    formatHours(want.actual),
+					Absent:    formatHours(want.absent),
+					Overtime:  formatHours(want.overtime),
+					Undertime: formatHours(want.undertime),
+				}
+				if got != wantPeriod {
+					t.Fatalf("period %d = %+v, want %+v", i, got, wantPeriod)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOvertimeReportByDay(t *testing.T) {
+	from := time.Date(2024, time.April, 24, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.May, 7, 0, 0, 0, 0, time.UTC)
+	ss := NewScheduleService(overtimeStore())
+
+	report, err := ss.GetOvertimeReport(userContext(), testUserUUID, from, to, models.GroupByDay)
+	if err != nil {
+		t.Fatalf("GetOvertimeReport() error = %v", err)
+	}
+	if len(report.Periods) != 14 {
+		t.Fatalf("GetOvertimeReport() returned %d periods, want one per day", len(report.Periods))
+	}
+
+	holiday := report.Periods[7]
+	if holiday.Start != "2024-05-01" || holiday.Expected != formatHours(0) || holiday.Undertime != formatHours(0) {
+		t.Fatalf("holiday period = %+v, want nothing expected", holiday)
+	}
+	halfDay := report.Periods[9]
+	if halfDay.Start != "2024-05-03" || halfDay.Expected != formatHours(4) || halfDay.Absent != formatHours(4) {
+		t.Fatalf("half day off period = %+v, want 4 hours expected and absent", halfDay)
+	}
+}
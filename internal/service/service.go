@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"time"
 	sqlc "time-tracker/internal/db/sqlc"
 	"time-tracker/internal/models"
+	"time-tracker/pkg/fieldcrypt"
+	"time-tracker/pkg/holidays"
+	"time-tracker/pkg/jwt"
 
 	"github.com/google/uuid"
 )
@@ -11,28 +15,135 @@ import (
 //go:generate mockery --name IUserService
 type IUserService interface {
 	CreateUser(ctx context.Context, payload *models.CreateUserPayload) (*models.User, error)
-	GetUsers(ctx context.Context, limit, offset int, filters map[string]string) ([]models.User, error)
+	GetUsers(ctx context.Context, limit, offset int, filters map[string]string, includeDeleted bool) ([]models.User, error)
 	GetUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error)
 	GetUserByPassportNumber(ctx context.Context, passportNumber string) (*models.User, error)
-	UpdateUserByUUID(ctx context.Context, UUID uuid.UUID, payload *models.UpdateUserPayload) (*models.User, error)
-	DeleteUserByUUID(ctx context.Context, UUID uuid.UUID) error
+	UpdateUserByUUID(ctx context.Context, UUID uuid.UUID, patch *models.Patch, versions []int32) (*models.User, error)
+	DeleteUserByUUID(ctx context.Context, UUID uuid.UUID, versions []int32) error
+	RestoreUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error)
+	PurgeUserByUUID(ctx context.Context, UUID uuid.UUID, versions []int32) error
+	SetUserRole(ctx context.Context, UUID uuid.UUID, payload *models.SetUserRolePayload) (*models.User, error)
+	GetPIIAccesses(ctx context.Context, UUID uuid.UUID, limit, offset int) ([]models.PIIAccess, error)
+	ExportUserData(ctx context.Context, UUID uuid.UUID) (*models.UserExport, error)
+	AnonymizeUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error)
 }
 
 //go:generate mockery --name ITaskService
 type ITaskService interface {
 	CreateTask(ctx context.Context, userUUID uuid.UUID, payload *models.CreateTaskPayload) (*models.Task, error)
-	FinishTask(ctx context.Context, userUUID uuid.UUID) (*models.CompletedTask, error)
+	FinishTask(ctx context.Context, userUUID uuid.UUID, payload *models.StopTaskPayload) (*models.CompletedTask, error)
+	SwitchTask(ctx context.Context, userUUID uuid.UUID, payload *models.SwitchTaskPayload) (*models.SwitchedTask, error)
+	ContinueTask(ctx context.Context, userUUID uuid.UUID, payload *models.ContinueTaskPayload) (*models.ContinuedTask, error)
 	GetTasksResult(ctx context.Context, userUUID uuid.UUID, days int) (*models.TasksResult, error)
+	SearchTasks(ctx context.Context, userUUID uuid.UUID, query string, team bool, from, to time.Time, limit, offset int) ([]models.TaskSearchResult, error)
+	SuggestTaskNames(ctx context.Context, userUUID uuid.UUID, query string, team bool, limit int) ([]models.TaskNameSuggestion, error)
+	GetTaskHistoryEntry(ctx context.Context, userUUID, entryUUID uuid.UUID) (*models.TaskHistory, error)
+	UpdateTaskHistoryEntry(ctx context.Context, userUUID, entryUUID uuid.UUID, payload *models.UpdateTaskHistoryPayload, versions []int32) (*models.TaskHistory, error)
+}
+
+//go:generate mockery --name IBudgetService
+type IBudgetService interface {
+	SetTaskBudget(ctx context.Context, userUUID uuid.UUID, payload *models.TaskBudgetPayload) (*models.TaskBudget, error)
+	GetTaskBudgets(ctx context.Context, userUUID uuid.UUID) ([]models.TaskBudget, error)
+	DeleteTaskBudget(ctx context.Context, userUUID, budgetUUID uuid.UUID) error
+	CheckRunningBudgets(ctx context.Context) error
+	WatchRunningBudgets(ctx context.Context, interval time.Duration)
+}
+
+//go:generate mockery --name IPomodoroService
+type IPomodoroService interface {
+	GetPomodoroSettings(ctx context.Context, userUUID uuid.UUID) (*models.PomodoroSettings, error)
+	UpdatePomodoroSettings(ctx context.Context, userUUID uuid.UUID, payload *models.UpdatePomodoroSettingsPayload) (*models.PomodoroSettings, error)
+	FinishExpiredIntervals(ctx context.Context) error
+	WatchPomodoros(ctx context.Context, interval time.Duration)
+}
+
+//go:generate mockery --name IScheduleService
+type IScheduleService interface {
+	SetWorkSchedule(ctx context.Context, userUUID uuid.UUID, effectiveFrom time.Time, payload *models.WorkSchedulePayload) (*models.WorkSchedule, error)
+	GetWorkSchedules(ctx context.Context, userUUID uuid.UUID) ([]models.WorkSchedule, error)
+	DeleteWorkSchedule(ctx context.Context, userUUID, scheduleUUID uuid.UUID) error
+	GetOvertimeReport(ctx context.Context, userUUID uuid.UUID, from, to time.Time, groupBy string) (*models.OvertimeReport, error)
+}
+
+//go:generate mockery --name IAbsenceService
+type IAbsenceService interface {
+	CreateAbsence(ctx context.Context, userUUID uuid.UUID, startDate, endDate time.Time, payload *models.CreateAbsencePayload) (*models.Absence, error)
+	GetAbsences(ctx context.Context, userUUID uuid.UUID, year int) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, userUUID, absenceUUID uuid.UUID) error
+	SetAbsenceBalance(ctx context.Context, userUUID uuid.UUID, payload *models.AbsenceBalancePayload) error
+	GetAbsenceBalances(ctx context.Context, userUUID uuid.UUID, year int) ([]models.AbsenceBalance, error)
+}
+
+//go:generate mockery --name IHolidayService
+type IHolidayService interface {
+	ImportHolidayCalendar(ctx context.Context, name string, region *string, days []holidays.Holiday) (*models.HolidayCalendarImport, error)
+	GetHolidayCalendars(ctx context.Context) ([]models.HolidayCalendar, error)
+	GetHolidays(ctx context.Context, calendarUUID uuid.UUID, year int) ([]models.Holiday, error)
+	DeleteHolidayCalendar(ctx context.Context, calendarUUID uuid.UUID) error
+	SetUserHolidayCalendar(ctx context.Context, userUUID, calendarUUID uuid.UUID) error
+	DeleteUserHolidayCalendar(ctx context.Context, userUUID uuid.UUID) error
+	SetTeamHolidayCalendar(ctx context.Context, managerUUID, calendarUUID uuid.UUID) error
+	DeleteTeamHolidayCalendar(ctx context.Context, managerUUID uuid.UUID) error
+}
+
+//go:generate mockery --name IIdempotencyService
+type IIdempotencyService interface {
+	ReserveIdempotencyKey(ctx context.Context, userUUID uuid.UUID, key, requestHash string) (*models.IdempotentResponse, error)
+	SaveIdempotentResponse(ctx context.Context, userUUID uuid.UUID, key string, response *models.IdempotentResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, userUUID uuid.UUID, key string) error
+	PurgeExpiredIdempotencyKeys(ctx context.Context) error
+	WatchIdempotencyKeys(ctx context.Context, interval time.Duration)
+}
+
+//go:generate mockery --name IAuthService
+type IAuthService interface {
+	Authenticate(ctx context.Context, token string) (*models.Principal, error)
+	CreateAPIKey(ctx context.Context, payload *models.CreateAPIKeyPayload) (*models.CreatedAPIKey, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyUUID uuid.UUID) error
+}
+
+//go:generate mockery --name ISSOService
+type ISSOService interface {
+	BeginLogin(ctx context.Context) (*models.SSOLogin, error)
+	CompleteLogin(ctx context.Context, stateToken, state, code string) (*models.Session, error)
+}
+
+//go:generate mockery --name IAuditService
+type IAuditService interface {
+	GetAuditEntries(ctx context.Context, filter *models.AuditFilter, limit, offset int) ([]models.AuditEntry, error)
 }
 
 type Service struct {
 	IUserService
 	ITaskService
+	IBudgetService
+	IPomodoroService
+	IScheduleService
+	IAbsenceService
+	IHolidayService
+	IIdempotencyService
+	IAuthService
+	ISSOService
+	IAuditService
 }
 
-func NewService(repository sqlc.Querier) *Service {
+// NewService builds the services. repository only sees the organization of the
+// caller; system sees every organization and serves authentication and the
+// background jobs.
+func NewService(repository, system sqlc.Store, notifier BudgetNotifier, taskSettings TaskSettings, idempotencyTTL time.Duration, verifier *jwt.Verifier, identityProvider IdentityProvider, sessions SessionSettings, keyring *fieldcrypt.Keyring, peopleInfo PeopleInfoProvider) *Service {
 	return &Service{
-		IUserService: NewUserService(repository),
-		ITaskService: NewTaskService(repository),
+		IUserService:        NewUserService(repository, keyring, peopleInfo),
+		ITaskService:        NewTaskService(repository, notifier, taskSettings),
+		IBudgetService:      NewBudgetService(repository, system, notifier),
+		IPomodoroService:    NewPomodoroService(repository, system),
+		IScheduleService:    NewScheduleService(repository),
+		IAbsenceService:     NewAbsenceService(repository),
+		IHolidayService:     NewHolidayService(repository),
+		IIdempotencyService: NewIdempotencyService(repository, system, idempotencyTTL),
+		IAuthService:        NewAuthService(repository, system, verifier),
+		ISSOService:         NewSSOService(system, identityProvider, sessions),
+		IAuditService:       NewAuditService(repository),
 	}
 }
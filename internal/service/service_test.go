@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	testOrganizationUUID = uuid.MustParse("00000000-0000-0000-0000-0000000000aa")
+	testUserUUID         = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+)
+
+// userContext returns a context authenticated as the test user.
+func userContext() context.Context {
+	userUUID := testUserUUID
+	return WithPrincipal(context.Background(), &models.Principal{
+		Subject:          "user",
+		Role:             models.RoleEmployee,
+		OrganizationUUID: testOrganizationUUID,
+		UserUUID:         &userUUID,
+	})
+}
+
+func pgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
+
+func pgTime(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+func pgDate(year int, month time.Month, day int) pgtype.Date {
+	return pgtype.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+// fakeStore keeps the data of the test user in memory. Queries the tests do
+// not expect reach the nil embedded Store and panic.
+type fakeStore struct {
+	db.Store
+
+	activeTask *db.Task
+	history    []db.GetTaskHistoryByUUIDRow
+	absentDays map[string]bool
+
+	schedules       []db.WorkSchedule
+	absences        []db.Absence
+	absenceBalances []db.AbsenceBalance
+	holidays        []db.Holiday
+	tracked         []db.GetDailyTrackedSecondsRow
+
+	notifiedThresholds []int32
+}
+
+func (f *fakeStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(f)
+}
+
+func (f *fakeStore) GetUserByUUID(ctx context.Context, userUuid pgtype.UUID) (db.User, error) {
+	if userUuid != pgUUID(testUserUUID) {
+		return db.User{}, pgx.ErrNoRows
+	}
+	return db.User{
+		Uuid:             userUuid,
+		Role:             models.RoleEmployee,
+		OrganizationUuid: pgUUID(testOrganizationUUID),
+	}, nil
+}
+
+func (f *fakeStore) CreateAuditEntry(ctx context.Context, arg db.CreateAuditEntryParams) (int64, error) {
+	return 1, nil
+}
+
+func (f *fakeStore) HasFullDayAbsenceOn(ctx context.Context, arg db.HasFullDayAbsenceOnParams) (bool, error) {
+	return f.absentDays[arg.Day.Time.Format(time.DateOnly)], nil
+}
+
+func (f *fakeStore) GetActiveTask(ctx context.Context, userUuid pgtype.UUID) (db.Task, error) {
+	if f.activeTask == nil {
+		return db.Task{}, pgx.ErrNoRows
+	}
+	return *f.activeTask, nil
+}
+
+func (f *fakeStore) CreateTask(ctx context.Context, arg db.CreateTaskParams) (db.Task, error) {
+	startTime := arg.StartTime
+	if !startTime.Valid {
+		startTime = pgTime(time.Now().UTC())
+	}
+	task := db.Task{
+		Uuid:        pgUUID(uuid.New()),
+		UserUuid:    arg.UserUuid,
+		Name:        arg.Name,
+		Description: arg.Description,
+		Notes:       arg.Notes,
+		Mode:        arg.Mode,
+		StartTime:   startTime,
+	}
+	if arg.PlannedMinutes.Valid {
+		task.PlannedEndTime = pgTime(startTime.Time.Add(time.Duration(arg.PlannedMinutes.Int32) * time.Minute))
+	}
+	f.activeTask = &task
+	return task, nil
+}
+
+// UpdateTaskEndTime ends the active task like the query, at the given time or
+// now but no later than the planned end.
+func (f *fakeStore) UpdateTaskEndTime(ctx context.Context, arg db.UpdateTaskEndTimeParams) (db.Task, error) {
+	if f.activeTask == nil {
+		return db.Task{}, pgx.ErrNoRows
+	}
+	endTime := time.Now().UTC()
+	if arg.EndTime.Valid {
+		endTime = arg.EndTime.Time
+	}
+	if f.activeTask.PlannedEndTime.Valid && f.activeTask.PlannedEndTime.Time.Before(endTime) {
+		endTime = f.activeTask.PlannedEndTime.Time
+	}
+	f.activeTask.EndTime = pgTime(endTime)
+	return *f.activeTask, nil
+}
+
+func (f *fakeStore) DeleteTask(ctx context.Context, userUuid pgtype.UUID) error {
+	f.activeTask = nil
+	return nil
+}
+
+func (f *fakeStore) CreateTaskHistory(ctx context.Context, arg db.CreateTaskHistoryParams) (db.CreateTaskHistoryRow, error) {
+	entry := db.GetTaskHistoryByUUIDRow{
+		Uuid:        pgUUID(uuid.New()),
+		UserUuid:    arg.UserUuid,
+		Name:        arg.Name,
+		Description: arg.Description,
+		Notes:       arg.Notes,
+		StartTime:   arg.StartTime,
+		EndTime:     arg.EndTime,
+		Mode:        arg.Mode,
+		Version:     1,
+	}
+	f.history = append(f.history, entry)
+
+	return db.CreateTaskHistoryRow{
+		Uuid:        entry.Uuid,
+		UserUuid:    entry.UserUuid,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Notes:       entry.Notes,
+		StartTime:   entry.StartTime,
+		EndTime:     entry.EndTime,
+		Mode:        entry.Mode,
+		Version:     entry.Version,
+		Duration:    utils.FormatDuration(int64(entry.EndTime.Time.Sub(entry.StartTime.Time).Seconds())),
+	}, nil
+}
+
+func (f *fakeStore) GetLastTaskHistory(ctx context.Context, userUuid pgtype.UUID) (db.GetLastTaskHistoryRow, error) {
+	if len(f.history) == 0 {
+		return db.GetLastTaskHistoryRow{}, pgx.ErrNoRows
+	}
+	return db.GetLastTaskHistoryRow(f.history[len(f.history)-1]), nil
+}
+
+func (f *fakeStore) GetLastTaskHistoryEndTime(ctx context.Context, userUuid pgtype.UUID) (pgtype.Timestamptz, error) {
+	if len(f.history) == 0 {
+		return pgtype.Timestamptz{}, nil
+	}
+	return f.history[len(f.history)-1].EndTime, nil
+}
+
+func (f *fakeStore) GetTaskHistoryByUUID(ctx context.Context, arg db.GetTaskHistoryByUUIDParams) (db.GetTaskHistoryByUUIDRow, error) {
+	for _, entry := range f.history {
+		if entry.Uuid == arg.EntryUuid && entry.UserUuid == arg.UserUuid {
+			return entry, nil
+		}
+	}
+	return db.GetTaskHistoryByUUIDRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeStore) DeleteTaskHistory(ctx context.Context, arg db.DeleteTaskHistoryParams) (int64, error) {
+	for i, entry := range f.history {
+		if entry.Uuid == arg.EntryUuid && entry.UserUuid == arg.UserUuid {
+			f.history = append(f.history[:i], f.history[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeStore) GetTaskBudgetUsageByName(ctx context.Context, arg db.GetTaskBudgetUsageByNameParams) (db.GetTaskBudgetUsageByNameRow, error) {
+	return db.GetTaskBudgetUsageByNameRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeStore) UpdateTaskBudgetNotifiedThreshold(ctx context.Context, arg db.UpdateTaskBudgetNotifiedThresholdParams) error {
+	f.notifiedThresholds = append(f.notifiedThresholds, arg.NotifiedThreshold)
+	return nil
+}
+
+func (f *fakeStore) GetWorkSchedules(ctx context.Context, userUuid pgtype.UUID) ([]db.WorkSchedule, error) {
+	return f.schedules, nil
+}
+
+func (f *fakeStore) GetAbsencesInRange(ctx context.Context, arg db.GetAbsencesInRangeParams) ([]db.Absence, error) {
+	return f.absences, nil
+}
+
+func (f *fakeStore) GetAbsenceBalances(ctx context.Context, arg db.GetAbsenceBalancesParams) ([]db.AbsenceBalance, error) {
+	return f.absenceBalances, nil
+}
+
+func (f *fakeStore) GetUserHolidaysInRange(ctx context.Context, arg db.GetUserHolidaysInRangeParams) ([]db.Holiday, error) {
+	return f.holidays, nil
+}
+
+func (f *fakeStore) GetDailyTrackedSeconds(ctx context.Context, arg db.GetDailyTrackedSecondsParams) ([]db.GetDailyTrackedSecondsRow, error) {
+	return f.tracked, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+	"time-tracker/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ssoStateTTL bounds the time a user may spend at the identity provider.
+	ssoStateTTL = 10 * time.Minute
+	// ssoStateAudience keeps login state tokens and sessions, signed with the
+	// same secret, from being used for one another.
+	ssoStateAudience = "time-tracker-sso-state"
+
+	SessionTokenType = "Bearer"
+)
+
+var (
+	ErrSSODisabled       = errors.New("single sign-on is not configured")
+	ErrSSOFailed         = errors.New("single sign-on failed")
+	ErrIdentityNotLinked = errors.New("no user matches the identity")
+)
+
+// IdentityProvider is the hook that signs users in with an external OpenID
+// provider.
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Authenticate(ctx context.Context, code, codeVerifier, nonce string) (*models.Identity, error)
+}
+
+// SessionSettings describes the tokens issued after a single sign-on. They are
+// JWTs accepted by AuthService, so Issuer and Audience must match its
+// verifier.
+type SessionSettings struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+type SSOService struct {
+	repository db.Store
+	provider   IdentityProvider
+	sessions   SessionSettings
+}
+
+func NewSSOService(repository db.Store, provider IdentityProvider, sessions SessionSettings) *SSOService {
+	return &SSOService{
+		repository: repository,
+		provider:   provider,
+		sessions:   sessions,
+	}
+}
+
+// ssoState is the login state kept by the browser between BeginLogin and
+// CompleteLogin.
+type ssoState struct {
+	jwt.Claims
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
+
+// BeginLogin returns the provider URL to send the user to, and the state
+// token to hand back to CompleteLogin with the callback.
+func (ss *SSOService) BeginLogin(ctx context.Context) (*models.SSOLogin, error) {
+	if ss.provider == nil || len(ss.sessions.Secret) == 0 {
+		return nil, ErrSSODisabled
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		secret, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+	}
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := ss.provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stateToken, err := jwt.SignHS256(ssoState{
+		Claims: jwt.Claims{
+			Audience:  jwt.Audience{ssoStateAudience},
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ssoStateTTL).Unix(),
+		},
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, ss.sessions.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SSOLogin{AuthURL: authURL, StateToken: stateToken}, nil
+}
+
+// CompleteLogin exchanges the code of the provider callback and issues a
+// session for the user of the identity. An identity seen for the first time
+// is linked to the user with the same verified email, when exactly one has
+// it.
+func (ss *SSOService) CompleteLogin(ctx context.Context, stateToken, state, code string) (*models.Session, error) {
+	if ss.provider == nil || len(ss.sessions.Secret) == 0 {
+		return nil, ErrSSODisabled
+	}
+
+	verifier := &jwt.Verifier{HMACSecret: ss.sessions.Secret, Audience: ssoStateAudience}
+	var login ssoState
+	if _, err := verifier.Verify(stateToken, &login); err != nil {
+		logrus.Infof("Rejected login state: %v", err)
+		return nil, ErrSSOFailed
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		return nil, ErrSSOFailed
+	}
+
+	identity, err := ss.provider.Authenticate(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		logrus.Infof("Rejected identity provider login: %v", err)
+		return nil, ErrSSOFailed
+	}
+
+	userRaw, err := ss.linkIdentity(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := jwt.Claims{
+		Subject:   uuid.UUID(userRaw.Uuid.Bytes).String(),
+		Issuer:    ss.sessions.Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ss.sessions.TTL).Unix(),
+	}
+	if ss.sessions.Audience != "" {
+		claims.Audience = jwt.Audience{ss.sessions.Audience}
+	}
+
+	token, err := jwt.SignHS256(claims, ss.sessions.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Session{Token: token, TokenType: SessionTokenType, ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC()}, nil
+}
+
+func (ss *SSOService) linkIdentity(ctx context.Context, identity *models.Identity) (db.User, error) {
+	var userRaw db.User
+	err := ss.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		userRaw, err = q.GetUserByIdentity(ctx, db.GetUserByIdentityParams{
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+		})
+		if err == nil {
+			if userRaw.DeletedAt.Valid {
+				return ErrUserDeactivated
+			}
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if identity.Email == "" || !identity.EmailVerified {
+			return ErrIdentityNotLinked
+		}
+		usersRaw, err := q.GetUsersByEmail(ctx, identity.Email)
+		if err != nil {
+			return err
+		}
+		// An email used in several organizations does not tell which user
+		// signed in.
+		if len(usersRaw) != 1 {
+			return ErrIdentityNotLinked
+		}
+		userRaw = usersRaw[0]
+
+		return q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			Issuer:   identity.Issuer,
+			Subject:  identity.Subject,
+			UserUuid: userRaw.Uuid,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrIdentityNotLinked) {
+			logrus.Infof("No user for identity %s of %s", identity.Subject, identity.Issuer)
+		}
+		return db.User{}, err
+	}
+
+	return userRaw, nil
+}
+
+func randomToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("error generating random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
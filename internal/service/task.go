@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 	db "time-tracker/internal/db/sqlc"
 	"time-tracker/internal/models"
 	"time-tracker/pkg/utils"
@@ -12,30 +14,288 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sirupsen/logrus"
 )
 
 var (
 	ErrTaskAlreadyExists = errors.New("task already exists")
 	ErrTaskNotFound      = errors.New("task not found")
+	ErrInvalidStartTime  = errors.New("invalid start time")
+	ErrInvalidEndTime    = errors.New("invalid end time")
+	ErrTaskEntryNotFound = errors.New("task history entry not found")
 )
 
+// TaskSettings tunes how timers may be started and stopped.
+type TaskSettings struct {
+	// MaxBackdate is how far in the past an explicit start or end time may lie.
+	MaxBackdate time.Duration
+	// ResumeGap is the longest pause after which continuing the last entry
+	// reopens it instead of starting a new one.
+	ResumeGap time.Duration
+}
+
+// likePatternEscaper escapes the LIKE wildcards of user input.
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type TaskService struct {
-	repository db.Querier
+	repository db.Store
+	notifier   BudgetNotifier
+	settings   TaskSettings
 }
 
-func NewTaskService(repository db.Querier) *TaskService {
+func NewTaskService(repository db.Store, notifier BudgetNotifier, settings TaskSettings) *TaskService {
 	return &TaskService{
 		repository: repository,
+		notifier:   notifier,
+		settings:   settings,
 	}
 }
 
 func (ts *TaskService) CreateTask(ctx context.Context, userUUID uuid.UUID, payload *models.CreateTaskPayload) (*models.Task, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	var task *models.Task
+	err := ts.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		task, err = ts.createTask(ctx, q, userUUID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (ts *TaskService) FinishTask(ctx context.Context, userUUID uuid.UUID, payload *models.StopTaskPayload) (*models.CompletedTask, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := ts.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var completedTask *models.CompletedTask
+	err = ts.repository.ExecTx(ctx, func(q db.Querier) error {
+		completedTask, err = ts.finishTask(ctx, q, userPgUUID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ts.checkTaskBudget(ctx, userPgUUID, completedTask.Name)
+
+	return completedTask, nil
+}
+
+// SwitchTask stops the active task and starts a new one in a single
+// transaction. Both sides use the same instant, the explicit start time of the
+// new task or the current time, so the new task starts exactly when the
+// previous one ends. A focus session past its planned end has already ended
+// then, so the new task starts at its planned end.
+func (ts *TaskService) SwitchTask(ctx context.Context, userUUID uuid.UUID, payload *models.SwitchTaskPayload) (*models.SwitchedTask, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := ts.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var switched models.SwitchedTask
+	err = ts.repository.ExecTx(ctx, func(q db.Querier) error {
+		startPayload := payload.Start
+		if startPayload.StartTime == nil {
+			activeTask, err := q.GetActiveTask(ctx, userPgUUID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrTaskNotFound
+				}
+				return err
+			}
+
+			switchTime := time.Now().UTC()
+			if activeTask.PlannedEndTime.Valid && activeTask.PlannedEndTime.Time.Before(switchTime) {
+				switchTime = activeTask.PlannedEndTime.Time.UTC()
+			}
+			startPayload.StartTime = &switchTime
+		}
+
+		stopPayload := &models.StopTaskPayload{Notes: payload.StopNotes, EndTime: startPayload.StartTime}
+		stopped, err := ts.finishTask(ctx, q, userPgUUID, stopPayload)
+		if err != nil {
+			return err
+		}
+
+		started, err := ts.createTask(ctx, q, userUUID, &startPayload)
+		if err != nil {
+			return err
+		}
+
+		switched.Stopped = *stopped
+		switched.Started = *started
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ts.checkTaskBudget(ctx, userPgUUID, switched.Stopped.Name)
+
+	return &switched, nil
+}
+
+// ContinueTask starts a new timer with the name, description and notes of the
+// latest history entry, or of the given one. When the latest entry ended less
+// than ResumeGap ago it is reopened with its original start instead.
+func (ts *TaskService) ContinueTask(ctx context.Context, userUUID uuid.UUID, payload *models.ContinueTaskPayload) (*models.ContinuedTask, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+
+	_, err := ts.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var continued models.ContinuedTask
+	err = ts.repository.ExecTx(ctx, func(q db.Querier) error {
+		lastEntry, err := q.GetLastTaskHistory(ctx, userPgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskEntryNotFound
+			}
+			return err
+		}
+
+		entry := db.GetTaskHistoryByUUIDRow(lastEntry)
+		if payload.EntryUUID != nil {
+			entry, err = q.GetTaskHistoryByUUID(ctx, db.GetTaskHistoryByUUIDParams{
+				EntryUuid: pgtype.UUID{Bytes: *payload.EntryUUID, Valid: true},
+				UserUuid:  userPgUUID,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrTaskEntryNotFound
+				}
+				return err
+			}
+		}
+
+		reopen := entry.Uuid == lastEntry.Uuid &&
+			entry.Mode == models.TaskModeRegular &&
+			time.Since(entry.EndTime.Time) < ts.settings.ResumeGap
+
+		var task *models.Task
+		if reopen {
+			// The reopened entry runs from now on, like a new timer.
+			if !payload.OverrideAbsence {
+				if err := checkAbsence(ctx, q, userPgUUID, time.Now().UTC()); err != nil {
+					return err
+				}
+			}
+			task, err = reopenTaskEntry(ctx, q, userPgUUID, entry)
+		} else {
+			task, err = ts.createTask(ctx, q, userUUID, &models.CreateTaskPayload{
+				Name:            entry.Name,
+				Description:     utils.FromPgText(entry.Description),
+				Notes:           utils.FromPgText(entry.Notes),
+				OverrideAbsence: payload.OverrideAbsence,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		continued.Task = *task
+		continued.Reopened = reopen
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &continued, nil
+}
+
+// checkTaskBudget fires the budget notification of a just finished task, if
+// the task has a budget. The task is already stopped, so errors are only
+// logged.
+func (ts *TaskService) checkTaskBudget(ctx context.Context, userPgUUID pgtype.UUID, name string) {
+	usageRaw, err := ts.repository.GetTaskBudgetUsageByName(ctx, db.GetTaskBudgetUsageByNameParams{
+		UserUuid: userPgUUID,
+		Name:     name,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logrus.Errorf("Error getting budget for task %q: %v", name, err)
+		}
+		return
+	}
+
+	if err := checkBudgetThreshold(ctx, ts.repository, ts.notifier, db.GetTaskBudgetsUsageRow(usageRaw)); err != nil {
+		logrus.Errorf("Error checking budget for task %q: %v", name, err)
+	}
+}
+
+func (ts *TaskService) createTask(ctx context.Context, repository db.Querier, userUUID uuid.UUID, payload *models.CreateTaskPayload) (*models.Task, error) {
 	params := db.CreateTaskParams{
-		UserUuid: pgtype.UUID{Bytes: userUUID, Valid: true},
-		Name:     payload.Name,
+		UserUuid:    pgtype.UUID{Bytes: userUUID, Valid: true},
+		Name:        payload.Name,
+		Description: utils.ToPgText(payload.Description),
+		Notes:       utils.ToPgText(payload.Notes),
+		Mode:        models.TaskModeRegular,
+	}
+
+	startTime := time.Now().UTC()
+	if payload.StartTime != nil {
+		startTime = payload.StartTime.UTC()
+		if err := ts.validateStartTime(ctx, repository, params.UserUuid, startTime); err != nil {
+			return nil, err
+		}
+		params.StartTime = pgtype.Timestamptz{Time: startTime, Valid: true}
+	}
+
+	if !payload.OverrideAbsence {
+		if err := checkAbsence(ctx, repository, params.UserUuid, startTime); err != nil {
+			return nil, err
+		}
 	}
 
-	taskRaw, err := ts.repository.CreateTask(ctx, params)
+	if payload.Mode == models.TaskModePomodoro {
+		settingsRaw, err := repository.GetOrCreatePomodoroSettings(ctx, params.UserUuid)
+		if err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				return nil, ErrForeignKeyViolation
+			}
+			return nil, err
+		}
+		params.Mode = models.TaskModeFocus
+		params.PlannedMinutes = pgtype.Int4{Int32: settingsRaw.FocusMinutes, Valid: true}
+	}
+
+	taskRaw, err := repository.CreateTask(ctx, params)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok {
 			switch pgErr.Code {
@@ -48,26 +308,50 @@ func (ts *TaskService) CreateTask(ctx context.Context, userUUID uuid.UUID, paylo
 		return nil, err
 	}
 
+	if err := auditTask(ctx, repository, nil, &taskRaw); err != nil {
+		return nil, err
+	}
+
+	if payload.EstimateMinutes != nil {
+		budgetParams := db.UpsertTaskBudgetParams{
+			UserUuid:        params.UserUuid,
+			Name:            payload.Name,
+			EstimateMinutes: int32(*payload.EstimateMinutes),
+		}
+		if _, err := repository.UpsertTaskBudget(ctx, budgetParams); err != nil {
+			return nil, err
+		}
+	}
+
 	task, err := utils.ConvertDBTaskToModelsTask(taskRaw)
 	if err != nil {
-		return nil, fmt.Errorf("error converting user: %v", err)
+		return nil, fmt.Errorf("error converting task: %v", err)
 	}
 
 	return task, nil
 }
 
-func (ts *TaskService) FinishTask(ctx context.Context, userUUID uuid.UUID) (*models.CompletedTask, error) {
-	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
-
-	_, err := ts.repository.GetUserByUUID(ctx, userPgUUID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserNotFound
+// finishTask stops the active task of the user and moves it to the history.
+func (ts *TaskService) finishTask(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID, payload *models.StopTaskPayload) (*models.CompletedTask, error) {
+	var endTime pgtype.Timestamptz
+	if payload.EndTime != nil {
+		activeTask, err := repository.GetActiveTask(ctx, userPgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrTaskNotFound
+			}
+			return nil, err
 		}
-		return nil, err
+		if err := ts.validateEndTime(activeTask, payload.EndTime.UTC()); err != nil {
+			return nil, err
+		}
+		endTime = pgtype.Timestamptz{Time: payload.EndTime.UTC(), Valid: true}
 	}
 
-	taskRaw, err := ts.repository.UpdateTaskEndTime(ctx, userPgUUID)
+	taskRaw, err := repository.UpdateTaskEndTime(ctx, db.UpdateTaskEndTimeParams{
+		EndTime:  endTime,
+		UserUuid: userPgUUID,
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrTaskNotFound
@@ -75,30 +359,171 @@ func (ts *TaskService) FinishTask(ctx context.Context, userUUID uuid.UUID) (*mod
 		return nil, err
 	}
 
+	// Breaks are not work time, so they end without a history entry.
+	if taskRaw.Mode == models.TaskModeBreak {
+		if err := repository.DeleteTask(ctx, userPgUUID); err != nil {
+			return nil, err
+		}
+		if err := auditTask(ctx, repository, &taskRaw, nil); err != nil {
+			return nil, err
+		}
+		return &models.CompletedTask{
+			Name:        taskRaw.Name,
+			Description: utils.FromPgText(taskRaw.Description),
+			Duration:    utils.FormatDuration(int64(taskRaw.EndTime.Time.Sub(taskRaw.StartTime.Time).Seconds())),
+		}, nil
+	}
+
 	params := db.CreateTaskHistoryParams{
-		UserUuid:  userPgUUID,
-		Name:      taskRaw.Name,
-		StartTime: taskRaw.StartTime,
-		EndTime:   taskRaw.EndTime,
+		UserUuid:    userPgUUID,
+		Name:        taskRaw.Name,
+		Description: taskRaw.Description,
+		Notes:       appendNotes(taskRaw.Notes, payload.Notes),
+		StartTime:   taskRaw.StartTime,
+		EndTime:     taskRaw.EndTime,
+		Mode:        taskRaw.Mode,
+		Interrupted: taskRaw.PlannedEndTime.Valid && taskRaw.EndTime.Time.Before(taskRaw.PlannedEndTime.Time),
 	}
 
-	taskHistoryRaw, err := ts.repository.CreateTaskHistory(ctx, params)
+	taskHistoryRaw, err := repository.CreateTaskHistory(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ts.repository.DeleteTask(ctx, userPgUUID)
+	err = repository.DeleteTask(ctx, userPgUUID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := auditTask(ctx, repository, &taskRaw, nil); err != nil {
+		return nil, err
+	}
+	if err := auditTaskHistory(ctx, repository, nil, createdTaskHistoryEntry(taskHistoryRaw)); err != nil {
+		return nil, err
+	}
+
 	return &models.CompletedTask{
-		Name:     taskHistoryRaw.Name,
-		Duration: taskHistoryRaw.Duration.(string),
+		Name:        taskHistoryRaw.Name,
+		Description: utils.FromPgText(params.Description),
+		Notes:       utils.FromPgText(params.Notes),
+		Duration:    taskHistoryRaw.Duration.(string),
 	}, nil
 }
 
+// checkAbsence returns ErrUserAbsent when the user has a full-day absence on
+// day.
+func checkAbsence(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID, day time.Time) error {
+	absent, err := repository.HasFullDayAbsenceOn(ctx, db.HasFullDayAbsenceOnParams{
+		UserUuid: userPgUUID,
+		Day:      pgtype.Date{Time: day, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if absent {
+		return ErrUserAbsent
+	}
+	return nil
+}
+
+// reopenTaskEntry moves a history entry back to the active task, keeping its
+// original start.
+func reopenTaskEntry(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID, entry db.GetTaskHistoryByUUIDRow) (*models.Task, error) {
+	if _, err := repository.DeleteTaskHistory(ctx, db.DeleteTaskHistoryParams{
+		EntryUuid: entry.Uuid,
+		UserUuid:  userPgUUID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := auditTaskHistory(ctx, repository, &entry, nil); err != nil {
+		return nil, err
+	}
+
+	taskRaw, err := repository.CreateTask(ctx, db.CreateTaskParams{
+		UserUuid:    userPgUUID,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Notes:       entry.Notes,
+		Mode:        models.TaskModeRegular,
+		StartTime:   entry.StartTime,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return nil, ErrTaskAlreadyExists
+		}
+		return nil, err
+	}
+
+	if err := auditTask(ctx, repository, nil, &taskRaw); err != nil {
+		return nil, err
+	}
+
+	task, err := utils.ConvertDBTaskToModelsTask(taskRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting task: %v", err)
+	}
+
+	return task, nil
+}
+
+// appendNotes adds the notes given when stopping to the notes the task
+// already had.
+func appendNotes(existing pgtype.Text, notes *string) pgtype.Text {
+	if notes == nil || *notes == "" {
+		return existing
+	}
+	if !existing.Valid || existing.String == "" {
+		return pgtype.Text{String: *notes, Valid: true}
+	}
+	return pgtype.Text{String: existing.String + "\n" + *notes, Valid: true}
+}
+
+// validateStartTime checks that a backdated start is not in the future, not
+// older than the backdating limit and not before the end of the last entry.
+func (ts *TaskService) validateStartTime(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID, startTime time.Time) error {
+	now := time.Now()
+	if startTime.After(now) {
+		return fmt.Errorf("%w: start time is in the future", ErrInvalidStartTime)
+	}
+	if now.Sub(startTime) > ts.settings.MaxBackdate {
+		return fmt.Errorf("%w: start time is more than %s ago", ErrInvalidStartTime, ts.settings.MaxBackdate)
+	}
+
+	lastEndTime, err := repository.GetLastTaskHistoryEndTime(ctx, userPgUUID)
+	if err != nil {
+		return err
+	}
+	if lastEndTime.Valid && startTime.Before(lastEndTime.Time) {
+		return fmt.Errorf("%w: start time is before the end of the previous entry at %s",
+			ErrInvalidStartTime, lastEndTime.Time.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// validateEndTime checks that an explicit end is not in the future, not older
+// than the backdating limit and not before the start of the task.
+func (ts *TaskService) validateEndTime(task db.Task, endTime time.Time) error {
+	now := time.Now()
+	if endTime.After(now) {
+		return fmt.Errorf("%w: end time is in the future", ErrInvalidEndTime)
+	}
+	if now.Sub(endTime) > ts.settings.MaxBackdate {
+		return fmt.Errorf("%w: end time is more than %s ago", ErrInvalidEndTime, ts.settings.MaxBackdate)
+	}
+	if endTime.Before(task.StartTime.Time) {
+		return fmt.Errorf("%w: end time is before the start of the task at %s",
+			ErrInvalidEndTime, task.StartTime.Time.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
 func (ts *TaskService) GetTasksResult(ctx context.Context, userUUID uuid.UUID, days int) (*models.TasksResult, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
 	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
 	_, err := ts.repository.GetUserByUUID(ctx, userPgUUID)
 	if err != nil {
@@ -125,13 +550,215 @@ func (ts *TaskService) GetTasksResult(ctx context.Context, userUUID uuid.UUID, d
 	var completedTasks = make([]models.CompletedTask, len(taskResultByPeriodRows))
 	for i, task := range taskResultByPeriodRows {
 		completedTasks[i] = models.CompletedTask{
-			Name:     task.TaskName,
-			Duration: task.Duration.(string),
+			Name:        task.TaskName,
+			Description: utils.FromPgText(task.Description),
+			Notes:       utils.FromPgText(task.Notes),
+			Duration:    task.Duration.(string),
+		}
+	}
+
+	budgets, err := getTaskBudgets(ctx, ts.repository, userPgUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	pomodoroRows, err := ts.repository.GetCompletedPomodorosByPeriod(ctx, db.GetCompletedPomodorosByPeriodParams{
+		UserUuid: userPgUUID,
+		Period:   params.Column1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pomodoros := make([]models.PomodoroDay, len(pomodoroRows))
+	for i, row := range pomodoroRows {
+		pomodoros[i] = models.PomodoroDay{
+			Date:      row.Day.Time.Format(time.DateOnly),
+			Completed: int(row.Completed),
 		}
 	}
 
 	return &models.TasksResult{
 		CompletedTask: completedTasks,
 		TotalDuration: taskResultByPeriodRows[0].TotalDuration.(string),
+		Budgets:       budgets,
+		Pomodoros:     pomodoros,
 	}, nil
 }
+
+// SearchTasks runs a full-text search over the names, descriptions and notes
+// of the user history entries started between from and to. Zero times leave
+// the range open. With team, the entries of the users managed by the user are
+// searched too.
+func (ts *TaskService) SearchTasks(ctx context.Context, userUUID uuid.UUID, query string, team bool, from, to time.Time, limit, offset int) ([]models.TaskSearchResult, error) {
+	var err error
+	if team {
+		err = authorizeTeam(ctx, ts.repository, userUUID)
+	} else {
+		err = authorizeUser(ctx, ts.repository, userUUID, accessRead)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+	_, err = ts.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	params := db.SearchTaskHistoryParams{
+		Query:        query,
+		UserUuid:     userPgUUID,
+		Team:         team,
+		FromTime:     pgtype.Timestamptz{Time: from, Valid: !from.IsZero()},
+		ToTime:       pgtype.Timestamptz{Time: to, Valid: !to.IsZero()},
+		ResultLimit:  int32(limit),
+		ResultOffset: int32(offset),
+	}
+
+	rows, err := ts.repository.SearchTaskHistory(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.TaskSearchResult, len(rows))
+	for i, row := range rows {
+		result, err := utils.ConvertDBTaskSearchRowToModelsTaskSearchResult(row)
+		if err != nil {
+			return nil, fmt.Errorf("error converting search result: %v", err)
+		}
+		results[i] = *result
+	}
+
+	return results, nil
+}
+
+// SuggestTaskNames returns past task names of the user that start with or
+// resemble query, ranked by similarity, how often and how recently they were
+// used. An empty query returns the most used recent names. With team, the
+// names used by the users managed by the user count too.
+func (ts *TaskService) SuggestTaskNames(ctx context.Context, userUUID uuid.UUID, query string, team bool, limit int) ([]models.TaskNameSuggestion, error) {
+	var err error
+	if team {
+		err = authorizeTeam(ctx, ts.repository, userUUID)
+	} else {
+		err = authorizeUser(ctx, ts.repository, userUUID, accessRead)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userPgUUID := pgtype.UUID{Bytes: userUUID, Valid: true}
+	_, err = ts.repository.GetUserByUUID(ctx, userPgUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	params := db.SuggestTaskNamesParams{
+		Query:       query,
+		UserUuid:    userPgUUID,
+		Team:        team,
+		Pattern:     likePatternEscaper.Replace(query) + "%",
+		ResultLimit: int32(limit),
+	}
+
+	rows, err := ts.repository.SuggestTaskNames(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.TaskNameSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = models.TaskNameSuggestion{
+			Name:     row.Name,
+			Uses:     int(row.Uses),
+			LastUsed: row.LastUsed.Time,
+		}
+	}
+
+	return suggestions, nil
+}
+
+func (ts *TaskService) GetTaskHistoryEntry(ctx context.Context, userUUID, entryUUID uuid.UUID) (*models.TaskHistory, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessRead); err != nil {
+		return nil, err
+	}
+
+	entryRaw, err := ts.repository.GetTaskHistoryByUUID(ctx, db.GetTaskHistoryByUUIDParams{
+		EntryUuid: pgtype.UUID{Bytes: entryUUID, Valid: true},
+		UserUuid:  pgtype.UUID{Bytes: userUUID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskEntryNotFound
+		}
+		return nil, err
+	}
+
+	entry, err := utils.ConvertDBTaskHistoryEntryToModelsTaskHistory(entryRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting task history entry: %v", err)
+	}
+
+	return entry, nil
+}
+
+// UpdateTaskHistoryEntry edits a recorded task when its version is one of
+// versions, or unconditionally when versions is nil.
+func (ts *TaskService) UpdateTaskHistoryEntry(ctx context.Context, userUUID, entryUUID uuid.UUID, payload *models.UpdateTaskHistoryPayload, versions []int32) (*models.TaskHistory, error) {
+	if err := authorizeUser(ctx, ts.repository, userUUID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	params := db.UpdateTaskHistoryParams{
+		Name:        utils.ToPgText(payload.Name),
+		Description: utils.ToPgText(payload.Description),
+		Notes:       utils.ToPgText(payload.Notes),
+		EntryUuid:   pgtype.UUID{Bytes: entryUUID, Valid: true},
+		UserUuid:    pgtype.UUID{Bytes: userUUID, Valid: true},
+		Versions:    versions,
+	}
+
+	var entryRaw db.GetTaskHistoryByUUIDRow
+	err := ts.repository.ExecTx(ctx, func(q db.Querier) error {
+		beforeRaw, err := q.GetTaskHistoryByUUIDForUpdate(ctx, db.GetTaskHistoryByUUIDForUpdateParams{
+			EntryUuid: params.EntryUuid,
+			UserUuid:  params.UserUuid,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskEntryNotFound
+			}
+			return err
+		}
+
+		updatedRaw, err := q.UpdateTaskHistory(ctx, params)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPreconditionFailed
+			}
+			return err
+		}
+		entryRaw = db.GetTaskHistoryByUUIDRow(updatedRaw)
+
+		before := db.GetTaskHistoryByUUIDRow(beforeRaw)
+		return auditTaskHistory(ctx, q, &before, &entryRaw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := utils.ConvertDBTaskHistoryEntryToModelsTaskHistory(entryRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting task history entry: %v", err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+	"time-tracker/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var testTaskSettings = TaskSettings{
+	MaxBackdate: 24 * time.Hour,
+	ResumeGap:   15 * time.Minute,
+}
+
+// historyEntry returns a history entry of the test user that ended endedAgo
+// ago.
+func historyEntry(name, mode string, endedAgo time.Duration) db.GetTaskHistoryByUUIDRow {
+	end := time.Now().UTC().Add(-endedAgo)
+	return db.GetTaskHistoryByUUIDRow{
+		Uuid:      pgUUID(uuid.New()),
+		UserUuid:  pgUUID(testUserUUID),
+		Name:      name,
+		StartTime: pgTime(end.Add(-time.Hour)),
+		EndTime:   pgTime(end),
+		Mode:      mode,
+		Version:   1,
+	}
+}
+
+func TestContinueTask(t *testing.T) {
+	older := historyEntry("review", models.TaskModeRegular, 2*time.Minute)
+
+	tests := []struct {
+		name         string
+		last         db.GetTaskHistoryByUUIDRow
+		entryUUID    *uuid.UUID
+		wantReopened bool
+		wantName     string
+	}{
+		{
+			name:         "recent entry is reopened",
+			last:         historyEntry("coding", models.TaskModeRegular, 5*time.Minute),
+			wantReopened: true,
+			wantName:     "coding",
+		},
+		{
+			name:     "entry past the resume gap starts a new one",
+			last:     historyEntry("coding", models.TaskModeRegular, time.Hour),
+			wantName: "coding",
+		},
+		{
+			name:     "focus interval starts a new one",
+			last:     historyEntry("coding", models.TaskModeFocus, time.Minute),
+			wantName: "coding",
+		},
+		{
+			name:      "other than the latest entry starts a new one",
+			last:      historyEntry("coding", models.TaskModeRegular, time.Minute),
+			entryUUID: (*uuid.UUID)(&older.Uuid.Bytes),
+			wantName:  "review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{history: []db.GetTaskHistoryByUUIDRow{older, tt.last}}
+			ts := NewTaskService(store, nil, testTaskSettings)
+
+			before := time.Now().UTC()
+			continued, err := ts.ContinueTask(userContext(), testUserUUID, &models.ContinueTaskPayload{EntryUUID: tt.entryUUID})
+			if err != nil {
+				t.Fatalf("ContinueTask() error = %v", err)
+			}
+
+			if continued.Reopened != tt.wantReopened {
+				t.Fatalf("ContinueTask() reopened = %v, want %v", continued.Reopened, tt.wantReopened)
+			}
+			if continued.Task.Name != tt.wantName || continued.Task.Mode != models.TaskModeRegular {
+				t.Fatalf("ContinueTask() task = %s in %s mode, want %s in regular mode", continued.Task.Name, continued.Task.Mode, tt.wantName)
+			}
+
+			if tt.wantReopened {
+				if !continued.Task.StartTime.Equal(tt.last.StartTime.Time) {
+					t.Fatalf("reopened task starts at %s, want the original start %s", continued.Task.StartTime, tt.last.StartTime.Time)
+				}
+				if len(store.history) != 1 {
+					t.Fatalf("history has %d entries after reopening, want 1", len(store.history))
+				}
+				return
+			}
+			if continued.Task.StartTime.Before(before) {
+				t.Fatalf("new task starts at %s, want now", continued.Task.StartTime)
+			}
+			if len(store.history) != 2 {
+				t.Fatalf("history has %d entries after continuing, want 2", len(store.history))
+			}
+		})
+	}
+}
+
+func TestContinueTaskChecksAbsences(t *testing.T) {
+	for _, endedAgo := range []time.Duration{5 * time.Minute, time.Hour} {
+		store := &fakeStore{
+			history:    []db.GetTaskHistoryByUUIDRow{historyEntry("coding", models.TaskModeRegular, endedAgo)},
+			absentDays: map[string]bool{time.Now().UTC().Format(time.DateOnly): true},
+		}
+		ts := NewTaskService(store, nil, testTaskSettings)
+
+		if _, err := ts.ContinueTask(userContext(), testUserUUID, &models.ContinueTaskPayload{}); !errors.Is(err, ErrUserAbsent) {
+			t.Fatalf("ContinueTask() on an absent day error = %v, want %v", err, ErrUserAbsent)
+		}
+		if store.activeTask != nil || len(store.history) != 1 {
+			t.Fatal("ContinueTask() on an absent day changed the timers")
+		}
+
+		if _, err := ts.ContinueTask(userContext(), testUserUUID, &models.ContinueTaskPayload{OverrideAbsence: true}); err != nil {
+			t.Fatalf("ContinueTask() overriding the absence error = %v", err)
+		}
+	}
+}
+
+func TestSwitchTask(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name         string
+		active       db.Task
+		wantSwitchAt time.Time
+	}{
+		{
+			name: "regular task",
+			active: db.Task{
+				Name:      "coding",
+				Mode:      models.TaskModeRegular,
+				StartTime: pgTime(now.Add(-time.Hour)),
+			},
+		},
+		{
+			name: "running focus session",
+			active: db.Task{
+				Name:           "coding",
+				Mode:           models.TaskModeFocus,
+				StartTime:      pgTime(now.Add(-10 * time.Minute)),
+				PlannedEndTime: pgTime(now.Add(15 * time.Minute)),
+			},
+		},
+		{
+			name: "expired focus session",
+			active: db.Task{
+				Name:           "coding",
+				Mode:           models.TaskModeFocus,
+				StartTime:      pgTime(now.Add(-40 * time.Minute)),
+				PlannedEndTime: pgTime(now.Add(-15 * time.Minute)),
+			},
+			wantSwitchAt: now.Add(-15 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active := tt.active
+			active.Uuid = pgUUID(uuid.New())
+			active.UserUuid = pgUUID(testUserUUID)
+			store := &fakeStore{activeTask: &active}
+			ts := NewTaskService(store, nil, testTaskSettings)
+
+			before := time.Now().UTC()
+			switched, err := ts.SwitchTask(userContext(), testUserUUID, &models.SwitchTaskPayload{
+				Start: models.CreateTaskPayload{Name: "meeting"},
+			})
+			if err != nil {
+				t.Fatalf("SwitchTask() error = %v", err)
+			}
+
+			if switched.Stopped.Name != "coding" || switched.Started.Name != "meeting" {
+				t.Fatalf("SwitchTask() switched from %q to %q", switched.Stopped.Name, switched.Started.Name)
+			}
+			if len(store.history) != 1 {
+				t.Fatalf("history has %d entries, want the stopped task", len(store.history))
+			}
+			stopped := store.history[0]
+			if !stopped.EndTime.Time.Equal(switched.Started.StartTime) {
+				t.Fatalf("stopped task ends at %s, new task starts at %s", stopped.EndTime.Time, switched.Started.StartTime)
+			}
+
+			if tt.wantSwitchAt.IsZero() {
+				if switched.Started.StartTime.Before(before) {
+					t.Fatalf("new task starts at %s, want now", switched.Started.StartTime)
+				}
+			} else if !switched.Started.StartTime.Equal(tt.wantSwitchAt) {
+				t.Fatalf("new task starts at %s, want the planned end %s", switched.Started.StartTime, tt.wantSwitchAt)
+			}
+
+			if store.activeTask == nil || store.activeTask.Name != "meeting" {
+				t.Fatal("SwitchTask() did not leave the new task running")
+			}
+		})
+	}
+}
+
+func TestSwitchTaskWithoutActiveTask(t *testing.T) {
+	store := &fakeStore{}
+	ts := NewTaskService(store, nil, testTaskSettings)
+
+	_, err := ts.SwitchTask(userContext(), testUserUUID, &models.SwitchTaskPayload{
+		Start: models.CreateTaskPayload{Name: "meeting"},
+	})
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("SwitchTask() error = %v, want %v", err, ErrTaskNotFound)
+	}
+	if store.activeTask != nil {
+		t.Fatal("SwitchTask() started a task without stopping one")
+	}
+}
@@ -1,11 +1,19 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
 	db "time-tracker/internal/db/sqlc"
 	"time-tracker/internal/models"
+	"time-tracker/pkg/fieldcrypt"
+	"time-tracker/pkg/jsonpatch"
 	"time-tracker/pkg/utils"
 
 	"github.com/google/uuid"
@@ -19,56 +27,165 @@ var (
 	ErrUserAlreadyExists   = errors.New("user already exists")
 	ErrUsersNotFound       = errors.New("no users found")
 	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrInvalidPatch        = errors.New("invalid patch")
+	ErrPatchTestFailed     = errors.New("patch test failed")
+	ErrInvalidManager      = errors.New("manager must be another user with the manager or admin role")
+	ErrUserActive          = errors.New("user must be deactivated before it is purged")
+	ErrUserDeactivated     = errors.New("user is deactivated")
+	ErrUserAnonymized      = errors.New("anonymized users cannot be restored")
+	ErrPeopleInfoDisabled  = errors.New("surname, name and address are required without the people info API")
+	ErrPersonNotFound      = errors.New("no person is registered for the passport number")
+	ErrPeopleInfoFailed    = errors.New("people info API is unavailable")
 )
 
+var passportNumberPattern = regexp.MustCompile(`^\d{4} \d{6}$`)
+
+// PeopleInfoProvider is the hook that looks up the personal data registered
+// for a passport. It returns nil without error for unknown passports.
+type PeopleInfoProvider interface {
+	GetPersonInfo(ctx context.Context, passportSerie, passportNumber string) (*models.PersonInfo, error)
+}
+
 type UserService struct {
-	repository db.Querier
+	repository db.Store
+	keyring    *fieldcrypt.Keyring
+	peopleInfo PeopleInfoProvider
 }
 
-func NewUserService(repository db.Querier) *UserService {
+func NewUserService(repository db.Store, keyring *fieldcrypt.Keyring, peopleInfo PeopleInfoProvider) *UserService {
 	return &UserService{
 		repository: repository,
+		keyring:    keyring,
+		peopleInfo: peopleInfo,
 	}
 }
 
+// CreateUser adds a user to the organization of the caller. The surname, name
+// and address missing from payload are taken from the people info API, with
+// the patronymic when payload has none.
 func (ps *UserService) CreateUser(ctx context.Context, payload *models.CreateUserPayload) (*models.User, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err = ps.completeUserPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
 	var patronymic pgtype.Text
 	if payload.Patronymic != nil {
 		patronymic = pgtype.Text{String: *payload.Patronymic, Valid: true}
 	}
 
+	ciphertext, index, err := ps.encryptPassportNumber(payload.PassportNumber)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.CreateUserParams{
-		PassportNumber: payload.PassportNumber,
-		Name:           payload.Name,
-		Surname:        payload.Surname,
-		Patronymic:     patronymic,
-		Address:        payload.Address,
+		OrganizationUuid:         pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		PassportNumberCiphertext: ciphertext,
+		PassportNumberIndex:      index,
+		Name:                     payload.Name,
+		Surname:                  payload.Surname,
+		Patronymic:               patronymic,
+		Address:                  payload.Address,
+		Email:                    utils.ToPgText(payload.Email),
 	}
 
-	userRaw, err := ps.repository.CreateUser(ctx, params)
-	if err != nil {
-		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			return nil, ErrUserAlreadyExists
+	var userRaw db.User
+	err = ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		userRaw, err = q.CreateUser(ctx, params)
+		if err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				return ErrUserAlreadyExists
+			}
+			return err
 		}
+
+		return ps.auditUser(ctx, q, models.AuditActionCreate, nil, &userRaw)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	user, err := utils.ConvertDBUserToModelsUser(userRaw)
+	user, err := ps.convertUser(userRaw)
 	if err != nil {
 		return nil, fmt.Errorf("error converting user: %v", err)
 	}
 
+	if err := revealPassportNumbers(ctx, ps.repository, "CreateUser", user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (ps *UserService) GetUsers(ctx context.Context, limit, offset int, filters map[string]string) ([]models.User, error) {
+// completeUserPayload returns a copy of payload completed with the people info
+// API, or payload itself when it is complete.
+func (ps *UserService) completeUserPayload(ctx context.Context, payload *models.CreateUserPayload) (*models.CreateUserPayload, error) {
+	if payload.Surname != "" && payload.Name != "" && payload.Address != "" {
+		return payload, nil
+	}
+	if ps.peopleInfo == nil {
+		return nil, ErrPeopleInfoDisabled
+	}
+
+	passportSerie, passportNumber, _ := strings.Cut(payload.PassportNumber, " ")
+	info, err := ps.peopleInfo.GetPersonInfo(ctx, passportSerie, passportNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPeopleInfoFailed, err)
+	}
+	if info == nil {
+		return nil, ErrPersonNotFound
+	}
+
+	completed := *payload
+	if completed.Surname == "" {
+		completed.Surname = info.Surname
+	}
+	if completed.Name == "" {
+		completed.Name = info.Name
+	}
+	if completed.Address == "" {
+		completed.Address = info.Address
+	}
+	if completed.Patronymic == nil {
+		completed.Patronymic = info.Patronymic
+	}
+	return &completed, nil
+}
+
+// GetUsers lists the users of the organization of the caller to admins and the
+// team of the caller, including themselves, to managers. Deactivated users are
+// only listed with includeDeleted.
+func (ps *UserService) GetUsers(ctx context.Context, limit, offset int, filters map[string]string, includeDeleted bool) ([]models.User, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin, models.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.GetUsersParams{
-		UserLimit:  int32(limit),
-		UserOffset: int32(offset),
+		OrganizationUuid: pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		IncludeDeleted:   includeDeleted,
+		UserLimit:        int32(limit),
+		UserOffset:       int32(offset),
+	}
+
+	if principal.Role == models.RoleManager {
+		if principal.UserUUID == nil {
+			return nil, ErrForbidden
+		}
+		params.TeamOf = pgtype.UUID{Bytes: *principal.UserUUID, Valid: true}
 	}
 
 	if passportNumber, ok := filters["passport_number"]; ok {
 		params.PassportNumber = utils.ToPgText(&passportNumber)
+		params.PassportNumberIndexes = ps.keyring.BlindIndexes(passportNumber)
 	}
 	if name, ok := filters["name"]; ok {
 		params.Name = utils.ToPgText(&name)
@@ -94,16 +211,29 @@ func (ps *UserService) GetUsers(ctx context.Context, limit, offset int, filters
 
 	users := make([]models.User, len(usersRaw))
 	for i, userRaw := range usersRaw {
-		user, err := utils.ConvertDBUserToModelsUser(userRaw)
+		user, err := ps.convertUser(userRaw)
 		if err != nil {
 			return nil, fmt.Errorf("error converting user: %v", err)
 		}
 		users[i] = *user
 	}
+
+	revealed := make([]*models.User, len(users))
+	for i := range users {
+		revealed[i] = &users[i]
+	}
+	if err := revealPassportNumbers(ctx, ps.repository, "GetUsers", revealed...); err != nil {
+		return nil, err
+	}
+
 	return users, nil
 }
 
 func (ps *UserService) GetUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error) {
+	if err := authorizeUser(ctx, ps.repository, UUID, accessRead); err != nil {
+		return nil, err
+	}
+
 	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
 
 	userRaw, err := ps.repository.GetUserByUUID(ctx, pgUUID)
@@ -114,16 +244,29 @@ func (ps *UserService) GetUserByUUID(ctx context.Context, UUID uuid.UUID) (*mode
 		return nil, err
 	}
 
-	user, err := utils.ConvertDBUserToModelsUser(userRaw)
+	user, err := ps.convertUser(userRaw)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert user: %w", err)
 	}
 
+	if err := revealPassportNumbers(ctx, ps.repository, "GetUserByUUID", user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
 func (ps *UserService) GetUserByPassportNumber(ctx context.Context, passportNumber string) (*models.User, error) {
-	userRaw, err := ps.repository.GetUserByPassportNumber(ctx, passportNumber)
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	userRaw, err := ps.repository.GetUserByPassportNumber(ctx, db.GetUserByPassportNumberParams{
+		OrganizationUuid:      pgtype.UUID{Bytes: principal.OrganizationUUID, Valid: true},
+		PassportNumberIndexes: ps.keyring.BlindIndexes(passportNumber),
+		PassportNumber:        utils.ToPgText(&passportNumber),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -131,57 +274,513 @@ func (ps *UserService) GetUserByPassportNumber(ctx context.Context, passportNumb
 		return nil, err
 	}
 
-	user, err := utils.ConvertDBUserToModelsUser(userRaw)
+	// Users the caller may not read are reported as missing, so passport
+	// numbers cannot be probed.
+	if err := authorizeUser(ctx, ps.repository, uuid.UUID(userRaw.Uuid.Bytes), accessRead); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	user, err := ps.convertUser(userRaw)
 	if err != nil {
 		return nil, fmt.Errorf("Error converting user: %v", err)
 	}
 
+	if err := revealPassportNumbers(ctx, ps.repository, "GetUserByPassportNumber", user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (ps *UserService) UpdateUserByUUID(ctx context.Context, UUID uuid.UUID, payload *models.UpdateUserPayload) (*models.User, error) {
-	params := db.UpdateUserByUUIDParams{
-		UserUuid:       pgtype.UUID{Bytes: UUID, Valid: true},
-		Name:           utils.ToPgText(payload.Name),
-		Surname:        utils.ToPgText(payload.Surname),
-		Patronymic:     utils.ToPgText(payload.Patronymic),
-		Address:        utils.ToPgText(payload.Address),
-		PassportNumber: utils.ToPgText(payload.PassportNumber),
+// UpdateUserByUUID applies a merge patch or JSON patch to the user when its
+// version is one of versions, or unconditionally when versions is nil. The
+// identity data of users is only changed by admins.
+func (ps *UserService) UpdateUserByUUID(ctx context.Context, UUID uuid.UUID, patch *models.Patch, versions []int32) (*models.User, error) {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessWrite); err != nil {
+		return nil, err
 	}
 
-	userRaw, err := ps.repository.UpdateUserByUUID(ctx, params)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserNotFound
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
+
+	var userRaw db.User
+	err := ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		userRaw, err = q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if versions != nil && !slices.Contains(versions, userRaw.Version) {
+			return ErrPreconditionFailed
+		}
+		before := userRaw
+
+		passportNumber, err := ps.decryptPassportNumber(userRaw)
+		if err != nil {
+			return err
+		}
+
+		document := newUserDocument(userRaw, passportNumber)
+		current, err := json.Marshal(document)
+		if err != nil {
+			return err
+		}
+
+		patched, err := applyPatch(patch, current)
+		if err != nil {
+			return err
+		}
+
+		patchedDocument, err := decodeUserDocument(patched)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		document = *patchedDocument
+
+		if normalized, err := json.Marshal(document); err == nil && bytes.Equal(normalized, current) {
+			return nil
+		}
+
+		ciphertext, index, err := ps.encryptPassportNumber(document.PassportNumber)
+		if err != nil {
+			return err
 		}
-		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			return nil, ErrUserAlreadyExists
+
+		userRaw, err = q.UpdateUserByUUID(ctx, db.UpdateUserByUUIDParams{
+			Surname:                  document.Surname,
+			Name:                     document.Name,
+			Patronymic:               utils.ToPgText(document.Patronymic),
+			Address:                  document.Address,
+			PassportNumberCiphertext: ciphertext,
+			PassportNumberIndex:      index,
+			Email:                    utils.ToPgText(document.Email),
+			UserUuid:                 pgUUID,
+		})
+		if err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				return ErrUserAlreadyExists
+			}
+			return err
 		}
+
+		return ps.auditUser(ctx, q, models.AuditActionUpdate, &before, &userRaw)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	user, err := utils.ConvertDBUserToModelsUser(userRaw)
+	user, err := ps.convertUser(userRaw)
 	if err != nil {
 		return nil, fmt.Errorf("Error converting user: %v", err)
 	}
 
+	if err := revealPassportNumbers(ctx, ps.repository, "UpdateUserByUUID", user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (ps *UserService) DeleteUserByUUID(ctx context.Context, UUID uuid.UUID) error {
+// DeleteUserByUUID deactivates the user when its version is one of versions,
+// or unconditionally when versions is nil. Deactivated users keep their data
+// but can no longer sign in or be changed, until they are restored.
+func (ps *UserService) DeleteUserByUUID(ctx context.Context, UUID uuid.UUID, versions []int32) error {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return err
+	}
+
 	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
 
-	_, err := ps.repository.GetUserByUUID(ctx, pgUUID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrUserNotFound
+	return ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		userRaw, err := q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
 		}
-		return err
+
+		if versions != nil && !slices.Contains(versions, userRaw.Version) {
+			return ErrPreconditionFailed
+		}
+		if userRaw.DeletedAt.Valid {
+			return nil
+		}
+
+		deactivated, err := q.DeactivateUserByUUID(ctx, pgUUID)
+		if err != nil {
+			return err
+		}
+
+		return ps.auditUser(ctx, q, models.AuditActionDelete, &userRaw, &deactivated)
+	})
+}
+
+// RestoreUserByUUID reactivates a deactivated user.
+func (ps *UserService) RestoreUserByUUID(ctx context.Context, UUID uuid.UUID) (*models.User, error) {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return nil, err
 	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
 
-	if err := ps.repository.DeleteUserByUUID(ctx, pgUUID); err != nil {
+	var userRaw db.User
+	err := ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		userRaw, err = q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if !userRaw.DeletedAt.Valid {
+			return nil
+		}
+		if userRaw.AnonymizedAt.Valid {
+			return ErrUserAnonymized
+		}
+		before := userRaw
+
+		userRaw, err = q.RestoreUserByUUID(ctx, pgUUID)
+		if err != nil {
+			return err
+		}
+
+		return ps.auditUser(ctx, q, models.AuditActionRestore, &before, &userRaw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ps.convertUser(userRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting user: %v", err)
+	}
+
+	if err := revealPassportNumbers(ctx, ps.repository, "RestoreUserByUUID", user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// PurgeUserByUUID deletes a deactivated user with all their data, when its
+// version is one of versions or unconditionally when versions is nil. Only
+// the audit log keeps a trace of the user.
+func (ps *UserService) PurgeUserByUUID(ctx context.Context, UUID uuid.UUID, versions []int32) error {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
 		return err
 	}
 
-	return nil
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
+
+	return ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		userRaw, err := q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if versions != nil && !slices.Contains(versions, userRaw.Version) {
+			return ErrPreconditionFailed
+		}
+		if !userRaw.DeletedAt.Valid {
+			return ErrUserActive
+		}
+
+		// The entry is written first, as it is recorded for the organization
+		// of the user.
+		if err := ps.auditUser(ctx, q, models.AuditActionPurge, &userRaw, nil); err != nil {
+			return err
+		}
+
+		return q.PurgeUserByUUID(ctx, pgUUID)
+	})
+}
+
+// SetUserRole assigns the role, the manager and the permissions of a user.
+// Only admins may change roles.
+func (ps *UserService) SetUserRole(ctx context.Context, UUID uuid.UUID, payload *models.SetUserRolePayload) (*models.User, error) {
+	principal, err := requireRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	pgUUID := pgtype.UUID{Bytes: UUID, Valid: true}
+
+	var userRaw db.User
+	err = ps.repository.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetUserByUUIDForUpdate(ctx, pgUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var managerPgUUID pgtype.UUID
+		if payload.ManagerUUID != nil {
+			if *payload.ManagerUUID == UUID {
+				return ErrInvalidManager
+			}
+			managerPgUUID = pgtype.UUID{Bytes: *payload.ManagerUUID, Valid: true}
+			managerRaw, err := q.GetUserByUUID(ctx, managerPgUUID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrInvalidManager
+				}
+				return err
+			}
+			if uuid.UUID(managerRaw.OrganizationUuid.Bytes) != principal.OrganizationUUID {
+				return ErrInvalidManager
+			}
+			if managerRaw.Role != models.RoleManager && managerRaw.Role != models.RoleAdmin {
+				return ErrInvalidManager
+			}
+		}
+
+		userRaw, err = q.SetUserRole(ctx, db.SetUserRoleParams{
+			Role:        payload.Role,
+			ManagerUuid: managerPgUUID,
+			Permissions: append([]string{}, payload.Permissions...),
+			UserUuid:    pgUUID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		return ps.auditUser(ctx, q, models.AuditActionUpdate, &before, &userRaw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ps.convertUser(userRaw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting user: %v", err)
+	}
+
+	if err := revealPassportNumbers(ctx, ps.repository, "SetUserRole", user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// encryptPassportNumber returns the ciphertext and blind index stored for a
+// passport number.
+func (ps *UserService) encryptPassportNumber(passportNumber string) (ciphertext, index pgtype.Text, err error) {
+	encrypted, err := ps.keyring.Encrypt(passportNumber)
+	if err != nil {
+		return pgtype.Text{}, pgtype.Text{}, fmt.Errorf("error encrypting passport number: %w", err)
+	}
+	return pgtype.Text{String: encrypted, Valid: true}, pgtype.Text{String: ps.keyring.BlindIndex(passportNumber), Valid: true}, nil
+}
+
+// decryptPassportNumber returns the passport number of a user, which is still
+// in plain text for users not yet migrated by the reencrypt command.
+func (ps *UserService) decryptPassportNumber(user db.User) (string, error) {
+	if !user.PassportNumberCiphertext.Valid {
+		return user.PassportNumber.String, nil
+	}
+
+	passportNumber, err := ps.keyring.Decrypt(user.PassportNumberCiphertext.String)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting passport number: %w", err)
+	}
+	return passportNumber, nil
+}
+
+func (ps *UserService) convertUser(user db.User) (*models.User, error) {
+	passportNumber, err := ps.decryptPassportNumber(user)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertDBUserToModelsUser(user, passportNumber)
+}
+
+// auditUser records a change to a user in the audit log. Passport numbers are
+// recorded masked.
+func (ps *UserService) auditUser(ctx context.Context, repository db.Querier, action string, before, after *db.User) error {
+	var (
+		userUUID  uuid.UUID
+		snapshots [2]any
+	)
+	for i, userRaw := range []*db.User{before, after} {
+		if userRaw == nil {
+			continue
+		}
+		user, err := ps.convertUser(*userRaw)
+		if err != nil {
+			return fmt.Errorf("error converting user: %v", err)
+		}
+		user.PassportNumber = maskPassportNumber(user.PassportNumber)
+		userUUID = user.UUID
+		snapshots[i] = user
+	}
+
+	return recordAuditAction(ctx, repository, action, models.AuditEntityUser, userUUID, userUUID, snapshots[0], snapshots[1])
+}
+
+// GetPIIAccesses lists the full reads of the personal data of a user, latest
+// first. Only admins may read the log.
+func (ps *UserService) GetPIIAccesses(ctx context.Context, UUID uuid.UUID, limit, offset int) ([]models.PIIAccess, error) {
+	if _, err := requireRole(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, ps.repository, UUID, accessManage); err != nil {
+		return nil, err
+	}
+
+	accessesRaw, err := ps.repository.GetPIIAccesses(ctx, db.GetPIIAccessesParams{
+		UserUuid:     pgtype.UUID{Bytes: UUID, Valid: true},
+		AccessLimit:  int32(limit),
+		AccessOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make([]models.PIIAccess, len(accessesRaw))
+	for i, accessRaw := range accessesRaw {
+		accesses[i] = utils.ConvertDBPIIAccessToModelsPIIAccess(accessRaw)
+	}
+	return accesses, nil
+}
+
+// userDocument is the representation of a user that patches apply to.
+type userDocument struct {
+	PassportNumber string  `json:"passportNumber"`
+	Surname        string  `json:"surname"`
+	Name           string  `json:"name"`
+	Patronymic     *string `json:"patronymic"`
+	Address        string  `json:"address"`
+	Email          *string `json:"email"`
+}
+
+func newUserDocument(user db.User, passportNumber string) userDocument {
+	return userDocument{
+		PassportNumber: passportNumber,
+		Surname:        user.Surname,
+		Name:           user.Name,
+		Patronymic:     utils.FromPgText(user.Patronymic),
+		Address:        user.Address,
+		Email:          utils.FromPgText(user.Email),
+	}
+}
+
+// decodeUserDocument reads a patched user, rejecting unknown members and
+// missing or null required ones.
+func decodeUserDocument(data []byte) (*userDocument, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return nil, errors.New("user must be a JSON object")
+	}
+
+	var document userDocument
+	required := []struct {
+		name  string
+		value *string
+	}{
+		{"passportNumber", &document.PassportNumber},
+		{"surname", &document.Surname},
+		{"name", &document.Name},
+		{"address", &document.Address},
+	}
+
+	known := map[string]bool{"patronymic": true, "email": true}
+	for _, field := range required {
+		known[field.name] = true
+	}
+	for name := range members {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	for _, field := range required {
+		raw, ok := members[field.name]
+		if !ok || string(raw) == "null" {
+			return nil, fmt.Errorf("%s is required and cannot be null", field.name)
+		}
+		if err := json.Unmarshal(raw, field.value); err != nil {
+			return nil, fmt.Errorf("%s must be a string", field.name)
+		}
+		if *field.value == "" {
+			return nil, fmt.Errorf("%s cannot be empty", field.name)
+		}
+	}
+
+	if raw, ok := members["patronymic"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &document.Patronymic); err != nil {
+			return nil, errors.New("patronymic must be a string or null")
+		}
+	}
+
+	if raw, ok := members["email"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &document.Email); err != nil {
+			return nil, errors.New("email must be a string or null")
+		}
+		if address, err := mail.ParseAddress(*document.Email); err != nil || address.Address != *document.Email {
+			return nil, errors.New("email must be a valid address")
+		}
+	}
+
+	if !passportNumberPattern.MatchString(document.PassportNumber) {
+		return nil, errors.New("passportNumber must be in the \"1234 567890\" format")
+	}
+
+	return &document, nil
+}
+
+func applyPatch(patch *models.Patch, document []byte) ([]byte, error) {
+	var (
+		patched []byte
+		err     error
+	)
+	switch patch.Type {
+	case models.PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(document, patch.Body)
+	case models.PatchTypeJSON:
+		patched, err = jsonpatch.Apply(document, patch.Body)
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", ErrInvalidPatch, patch.Type)
+	}
+
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, ErrPatchTestFailed
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return patched, nil
 }
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"time"
+	db "time-tracker/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// workCalendar answers how many minutes a user is expected to work on a given
+// day. Schedules are ordered by effective_from, so the last one starting on or
+// before the day is the one in force. Absences reduce the expected time of the
+// days they cover and public holidays of the user calendar, or of the team
+// calendar when the user has none, are days off.
+type workCalendar struct {
+	schedules []db.WorkSchedule
+	absences  []db.Absence
+	holidays  map[string]string
+}
+
+// newWorkCalendar loads the schedules of a user and the absences and holidays
+// within the from-to range.
+func newWorkCalendar(ctx context.Context, repository db.Querier, userPgUUID pgtype.UUID, from, to time.Time) (*workCalendar, error) {
+	schedules, err := repository.GetWorkSchedules(ctx, userPgUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	absences, err := repository.GetAbsencesInRange(ctx, db.GetAbsencesInRangeParams{
+		UserUuid: userPgUUID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	holidaysRaw, err := repository.GetUserHolidaysInRange(ctx, db.GetUserHolidaysInRangeParams{
+		UserUuid: userPgUUID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := make(map[string]string, len(holidaysRaw))
+	for _, holiday := range holidaysRaw {
+		holidays[holiday.Day.Time.Format(time.DateOnly)] = holiday.Name
+	}
+
+	return &workCalendar{schedules: schedules, absences: absences, holidays: holidays}, nil
+}
+
+// expectedMinutes is the scheduled time of the day minus the absent part.
+func (wc *workCalendar) expectedMinutes(day time.Time) int {
+	return wc.scheduledMinutes(day) - wc.absentMinutes(day)
+}
+
+// absentMinutes is the part of the scheduled time covered by an absence.
+func (wc *workCalendar) absentMinutes(day time.Time) int {
+	return int(float64(wc.scheduledMinutes(day)) * wc.absenceFraction(day))
+}
+
+// isWorkingDay reports whether the day counts against leave balances. Without
+// any schedule in force Monday to Friday are working days.
+func (wc *workCalendar) isWorkingDay(day time.Time) bool {
+	if wc.isHoliday(day) {
+		return false
+	}
+	if wc.scheduleOn(day) == nil {
+		return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+	}
+	return wc.scheduledMinutes(day) > 0
+}
+
+// absenceFraction is 1 for a full absent day, 0.5 for a half day and 0 otherwise.
+func (wc *workCalendar) absenceFraction(day time.Time) float64 {
+	for _, absence := range wc.absences {
+		if fraction := absenceDayFraction(absence, day); fraction > 0 {
+			return fraction
+		}
+	}
+	return 0
+}
+
+func (wc *workCalendar) isHoliday(day time.Time) bool {
+	_, ok := wc.holidays[day.Format(time.DateOnly)]
+	return ok
+}
+
+func (wc *workCalendar) scheduleOn(day time.Time) *db.WorkSchedule {
+	var schedule *db.WorkSchedule
+	for i := range wc.schedules {
+		if wc.schedules[i].EffectiveFrom.Time.After(day) {
+			break
+		}
+		schedule = &wc.schedules[i]
+	}
+	return schedule
+}
+
+func (wc *workCalendar) scheduledMinutes(day time.Time) int {
+	schedule := wc.scheduleOn(day)
+	if schedule == nil || wc.isHoliday(day) {
+		return 0
+	}
+
+	switch day.Weekday() {
+	case time.Monday:
+		return int(schedule.MondayMinutes)
+	case time.Tuesday:
+		return int(schedule.TuesdayMinutes)
+	case time.Wednesday:
+		return int(schedule.WednesdayMinutes)
+	case time.Thursday:
+		return int(schedule.ThursdayMinutes)
+	case time.Friday:
+		return int(schedule.FridayMinutes)
+	case time.Saturday:
+		return int(schedule.SaturdayMinutes)
+	default:
+		return int(schedule.SundayMinutes)
+	}
+}
+
+func absenceDayFraction(absence db.Absence, day time.Time) float64 {
+	if day.Before(absence.StartDate.Time) || day.After(absence.EndDate.Time) {
+		return 0
+	}
+	if (absence.HalfDayStart && day.Equal(absence.StartDate.Time)) ||
+		(absence.HalfDayEnd && day.Equal(absence.EndDate.Time)) {
+		return 0.5
+	}
+	return 1
+}
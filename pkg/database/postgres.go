@@ -3,15 +3,21 @@ package database
 import (
 	"context"
 	"fmt"
-	"time-tracker/internal/config"
+	repository "time-tracker/internal/db/sqlc"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPostgresDB(cfg *config.Config) (*pgxpool.Pool, error) {
+func NewPostgresDB(source string) (*pgxpool.Pool, error) {
 	ctx := context.Background()
 
-	pgxPool, err := pgxpool.New(ctx, cfg.DBSource)
+	poolConfig, err := pgxpool.ParseConfig(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database source: %w", err)
+	}
+	repository.ConfigureTenantIsolation(poolConfig)
+
+	pgxPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
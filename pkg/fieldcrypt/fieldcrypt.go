@@ -0,0 +1,177 @@
+// Package fieldcrypt encrypts individual database values with AES-256-GCM and
+// derives blind indexes, keyed HMAC-SHA256 digests, to look encrypted values
+// up by equality.
+//
+// Ciphertexts name the key that sealed them, so keys can be rotated: new
+// values are sealed with the current key while the previous keys still open
+// older values until they are re-encrypted.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of encryption and index keys.
+const KeySize = 32
+
+var (
+	ErrMalformed  = errors.New("malformed ciphertext")
+	ErrUnknownKey = errors.New("unknown encryption key")
+	ErrDecrypt    = errors.New("ciphertext cannot be decrypted")
+)
+
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// ParseKeys reads keys written as "<id>:<base64 secret>".
+func ParseKeys(specs []string) ([]Key, error) {
+	keys := make([]Key, 0, len(specs))
+	for _, spec := range specs {
+		id, encoded, found := strings.Cut(strings.TrimSpace(spec), ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("key must be written as <id>:<base64 secret>")
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Keyring holds the encryption and index keys. The first key of each list is
+// the current one.
+type Keyring struct {
+	current   string
+	aeads     map[string]cipher.AEAD
+	indexKeys [][]byte
+}
+
+func NewKeyring(encryptionKeys, indexKeys []Key) (*Keyring, error) {
+	if len(encryptionKeys) == 0 || len(indexKeys) == 0 {
+		return nil, errors.New("at least one encryption key and one index key are required")
+	}
+
+	k := &Keyring{
+		current: encryptionKeys[0].ID,
+		aeads:   make(map[string]cipher.AEAD, len(encryptionKeys)),
+	}
+	for _, key := range encryptionKeys {
+		if len(key.Secret) != KeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes", key.ID, KeySize)
+		}
+		if _, ok := k.aeads[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key %q", key.ID)
+		}
+
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.aeads[key.ID] = aead
+	}
+
+	for _, key := range indexKeys {
+		if len(key.Secret) != KeySize {
+			return nil, fmt.Errorf("index key %q must be %d bytes", key.ID, KeySize)
+		}
+		k.indexKeys = append(k.indexKeys, key.Secret)
+	}
+
+	return k, nil
+}
+
+// ParseKeyring builds a keyring from keys written as for ParseKeys.
+func ParseKeyring(encryptionKeys, indexKeys []string) (*Keyring, error) {
+	encryption, err := ParseKeys(encryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ParseKeys(indexKeys)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(encryption, index)
+}
+
+// Encrypt seals plaintext with the current key as "<key id>:<base64 data>".
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.aeads[k.current]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.current))
+	return k.current + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext sealed with any key of the keyring.
+func (k *Keyring) Decrypt(ciphertext string) (string, error) {
+	id, encoded, found := strings.Cut(ciphertext, ":")
+	if !found {
+		return "", ErrMalformed
+	}
+
+	aead, ok := k.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	nonce, data := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, data, []byte(id))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// IsCurrent reports whether ciphertext is sealed with the current key.
+func (k *Keyring) IsCurrent(ciphertext string) bool {
+	return strings.HasPrefix(ciphertext, k.current+":")
+}
+
+// BlindIndex returns the index of value under the current index key, to store
+// next to its ciphertext.
+func (k *Keyring) BlindIndex(value string) string {
+	return blindIndex(k.indexKeys[0], value)
+}
+
+// BlindIndexes returns the index of value under every index key, to look up
+// rows whose index is not yet rebuilt with the current key.
+func (k *Keyring) BlindIndexes(value string) []string {
+	indexes := make([]string, len(k.indexKeys))
+	for i, key := range k.indexKeys {
+		indexes[i] = blindIndex(key, value)
+	}
+	return indexes
+}
+
+func blindIndex(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,180 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{fill}, KeySize)}
+}
+
+func newTestKeyring(t *testing.T, encryptionKeys, indexKeys []Key) *Keyring {
+	t.Helper()
+
+	keyring, err := NewKeyring(encryptionKeys, indexKeys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	return keyring
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring := newTestKeyring(t, []Key{testKey("k1", 1)}, []Key{testKey("i1", 2)})
+
+	for _, plaintext := range []string{"1234 567890", "", "пример"} {
+		ciphertext, err := keyring.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) error = %v", plaintext, err)
+		}
+		if !strings.HasPrefix(ciphertext, "k1:") || !keyring.IsCurrent(ciphertext) {
+			t.Fatalf("Encrypt(%q) = %q, want a ciphertext of the current key", plaintext, ciphertext)
+		}
+		if plaintext != "" && strings.Contains(ciphertext, plaintext) {
+			t.Fatalf("Encrypt(%q) = %q holds the plaintext", plaintext, ciphertext)
+		}
+
+		got, err := keyring.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if got != plaintext {
+			t.Fatalf("Decrypt() = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestEncryptUsesFreshNonces(t *testing.T) {
+	keyring := newTestKeyring(t, []Key{testKey("k1", 1)}, []Key{testKey("i1", 2)})
+
+	first, _ := keyring.Encrypt("1234 567890")
+	second, _ := keyring.Encrypt("1234 567890")
+	if first == second {
+		t.Fatal("Encrypt() returned the same ciphertext twice")
+	}
+}
+
+func TestDecryptRejectsTamperedCiphertexts(t *testing.T) {
+	keyring := newTestKeyring(t, []Key{testKey("k1", 1), testKey("k2", 3)}, []Key{testKey("i1", 2)})
+
+	ciphertext, err := keyring.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	_, encoded, _ := strings.Cut(ciphertext, ":")
+	sealed, _ := base64.RawStdEncoding.DecodeString(encoded)
+	sealed[len(sealed)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		ciphertext string
+		want       error
+	}{
+		{"flipped bit", "k1:" + base64.RawStdEncoding.EncodeToString(sealed), ErrDecrypt},
+		// The key id is authenticated, so a ciphertext cannot be relabeled.
+		{"other key id", "k2:" + encoded, ErrDecrypt},
+		{"unknown key id", "k9:" + encoded, ErrUnknownKey},
+		{"missing key id", encoded, ErrMalformed},
+		{"invalid base64", "k1:***", ErrMalformed},
+		{"too short", "k1:AAAA", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := keyring.Decrypt(tt.ciphertext); !errors.Is(err, tt.want) {
+				t.Fatalf("Decrypt() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := newTestKeyring(t, []Key{testKey("k1", 1)}, []Key{testKey("i1", 2)})
+	oldCiphertext, err := old.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	oldIndex := old.BlindIndex("1234 567890")
+
+	rotated := newTestKeyring(t,
+		[]Key{testKey("k2", 3), testKey("k1", 1)},
+		[]Key{testKey("i2", 4), testKey("i1", 2)},
+	)
+
+	got, err := rotated.Decrypt(oldCiphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() after rotation error = %v", err)
+	}
+	if got != "1234 567890" {
+		t.Fatalf("Decrypt() after rotation = %q", got)
+	}
+	if rotated.IsCurrent(oldCiphertext) {
+		t.Fatal("IsCurrent() = true for a ciphertext of the previous key")
+	}
+
+	newCiphertext, err := rotated.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(newCiphertext, "k2:") {
+		t.Fatalf("Encrypt() after rotation = %q, want the k2 key", newCiphertext)
+	}
+	if _, err := old.Decrypt(newCiphertext); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Decrypt() with the old keyring error = %v, want %v", err, ErrUnknownKey)
+	}
+
+	indexes := rotated.BlindIndexes("1234 567890")
+	if len(indexes) != 2 || indexes[0] != rotated.BlindIndex("1234 567890") || indexes[1] != oldIndex {
+		t.Fatalf("BlindIndexes() = %v, want the current index then %q", indexes, oldIndex)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	keyring := newTestKeyring(t, []Key{testKey("k1", 1)}, []Key{testKey("i1", 2)})
+	same := newTestKeyring(t, []Key{testKey("k9", 9)}, []Key{testKey("i1", 2)})
+	other := newTestKeyring(t, []Key{testKey("k1", 1)}, []Key{testKey("i2", 4)})
+
+	index := keyring.BlindIndex("1234 567890")
+	if len(index) != 64 {
+		t.Fatalf("BlindIndex() = %q, want 64 hex digits", index)
+	}
+	if keyring.BlindIndex("1234 567890") != index || same.BlindIndex("1234 567890") != index {
+		t.Fatal("BlindIndex() is not stable for the same index key")
+	}
+	if keyring.BlindIndex("1234 567891") == index {
+		t.Fatal("BlindIndex() is the same for different values")
+	}
+	if other.BlindIndex("1234 567890") == index {
+		t.Fatal("BlindIndex() is the same under different index keys")
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize))
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+
+	if _, err := ParseKeyring([]string{"k1:" + secret}, []string{" i1:" + secret + " "}); err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		name                      string
+		encryptionKeys, indexKeys []string
+	}{
+		{"no encryption key", nil, []string{"i1:" + secret}},
+		{"no index key", []string{"k1:" + secret}, nil},
+		{"missing id", []string{secret}, []string{"i1:" + secret}},
+		{"invalid base64", []string{"k1:***"}, []string{"i1:" + secret}},
+		{"short encryption key", []string{"k1:" + short}, []string{"i1:" + secret}},
+		{"short index key", []string{"k1:" + secret}, []string{"i1:" + short}},
+		{"duplicate encryption key", []string{"k1:" + secret, "k1:" + secret}, []string{"i1:" + secret}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseKeyring(tt.encryptionKeys, tt.indexKeys); err == nil {
+				t.Fatal("ParseKeyring() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package holidays
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Holiday is a single non-working day of a calendar.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// Parse reads holidays from an ICS or JSON file, chosen by the file extension.
+// The result is sorted by date with one entry per day.
+func Parse(filename string, data []byte) ([]Holiday, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics", ".ical":
+		return ParseICS(data)
+	case ".json":
+		return ParseJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported holiday file format %q", filepath.Ext(filename))
+	}
+}
+
+// ParseJSON reads an array of {"date": "YYYY-MM-DD", "name": "..."} objects.
+func ParseJSON(data []byte) ([]Holiday, error) {
+	var entries []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid holiday JSON: %w", err)
+	}
+
+	holidays := make([]Holiday, 0, len(entries))
+	for _, entry := range entries {
+		date, err := time.Parse(time.DateOnly, entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q", entry.Date)
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: entry.Name})
+	}
+
+	return normalize(holidays), nil
+}
+
+// ParseICS reads the all-day VEVENTs of an iCalendar file. Events spanning
+// several days produce one holiday per day; DTEND is exclusive as in RFC 5545.
+func ParseICS(data []byte) ([]Holiday, error) {
+	var holidays []Holiday
+	var inEvent bool
+	var name string
+	var start, end time.Time
+
+	for _, line := range unfoldICS(data) {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		property, _, _ := strings.Cut(key, ";")
+
+		switch strings.ToUpper(property) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				name, start, end = "", time.Time{}, time.Time{}
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", name)
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				holidays = append(holidays, Holiday{Date: day, Name: name})
+			}
+		case "SUMMARY":
+			if inEvent {
+				name = unescapeICS(value)
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			date, err := parseICSDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(property, "DTSTART") {
+				start = date
+			} else {
+				end = date
+			}
+		}
+	}
+
+	return normalize(holidays), nil
+}
+
+// unfoldICS splits the content into logical lines, joining continuation lines
+// that start with a space or a tab.
+func unfoldICS(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid ICS date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ICS date %q", value)
+	}
+	return date, nil
+}
+
+func unescapeICS(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
+
+// normalize sorts holidays by date and keeps the first name seen for a day.
+func normalize(holidays []Holiday) []Holiday {
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	result := make([]Holiday, 0, len(holidays))
+	for _, holiday := range holidays {
+		if len(result) > 0 && result[len(result)-1].Date.Equal(holiday.Date) {
+			continue
+		}
+		result = append(result, holiday)
+	}
+	return result
+}
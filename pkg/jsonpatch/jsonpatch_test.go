@@ -0,0 +1,221 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// The cases of RFC 6902 Appendix A, followed by the edge cases of this
+// implementation.
+var applyTests = []struct {
+	name    string
+	doc     string
+	patch   string
+	want    string // Empty when the patch fails
+	wantErr error  // Checked with errors.Is when set
+}{
+	{
+		name:  "A.1 adding an object member",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+		want:  `{"baz": "qux", "foo": "bar"}`,
+	},
+	{
+		name:  "A.2 adding an array element",
+		doc:   `{"foo": ["bar", "baz"]}`,
+		patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+		want:  `{"foo": ["bar", "qux", "baz"]}`,
+	},
+	{
+		name:  "A.3 removing an object member",
+		doc:   `{"baz": "qux", "foo": "bar"}`,
+		patch: `[{"op": "remove", "path": "/baz"}]`,
+		want:  `{"foo": "bar"}`,
+	},
+	{
+		name:  "A.4 removing an array element",
+		doc:   `{"foo": ["bar", "qux", "baz"]}`,
+		patch: `[{"op": "remove", "path": "/foo/1"}]`,
+		want:  `{"foo": ["bar", "baz"]}`,
+	},
+	{
+		name:  "A.5 replacing a value",
+		doc:   `{"baz": "qux", "foo": "bar"}`,
+		patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+		want:  `{"baz": "boo", "foo": "bar"}`,
+	},
+	{
+		name:  "A.6 moving a value",
+		doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+		patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+		want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+	},
+	{
+		name:  "A.7 moving an array element",
+		doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+		patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+		want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+	},
+	{
+		name: "A.8 testing a value: success",
+		doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		patch: `[
+			{"op": "test", "path": "/baz", "value": "qux"},
+			{"op": "test", "path": "/foo/1", "value": 2}
+		]`,
+		want: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+	},
+	{
+		name:    "A.9 testing a value: error",
+		doc:     `{"baz": "qux"}`,
+		patch:   `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+		wantErr: ErrTestFailed,
+	},
+	{
+		name:  "A.10 adding a nested member object",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+		want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+	},
+	{
+		name:  "A.11 ignoring unrecognized elements",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+		want:  `{"foo": "bar", "baz": "qux"}`,
+	},
+	{
+		name:  "A.12 adding to a nonexistent target",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+	},
+	{
+		name:  "A.13 invalid JSON patch document",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+	},
+	{
+		name:  "A.14 ~ escape ordering",
+		doc:   `{"/": 9, "~1": 10}`,
+		patch: `[{"op": "test", "path": "/~01", "value": 10}]`,
+		want:  `{"/": 9, "~1": 10}`,
+	},
+	{
+		name:    "A.15 comparing strings and numbers",
+		doc:     `{"/": 9, "~1": 10}`,
+		patch:   `[{"op": "test", "path": "/~01", "value": "10"}]`,
+		wantErr: ErrTestFailed,
+	},
+	{
+		name:  "A.16 adding an array value",
+		doc:   `{"foo": ["bar"]}`,
+		patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+		want:  `{"foo": ["bar", ["abc", "def"]]}`,
+	},
+	{
+		name:  "numbers are tested by value",
+		doc:   `{"foo": 1, "bar": [1.5, {"baz": 100}]}`,
+		patch: `[{"op": "test", "path": "/foo", "value": 1.0}, {"op": "test", "path": "/bar", "value": [15e-1, {"baz": 1e2}]}]`,
+		want:  `{"foo": 1, "bar": [1.5, {"baz": 100}]}`,
+	},
+	{
+		name:    "objects with other members are not equal",
+		doc:     `{"foo": {"bar": 1}}`,
+		patch:   `[{"op": "test", "path": "/foo", "value": {"bar": 1, "baz": 2}}]`,
+		wantErr: ErrTestFailed,
+	},
+	{
+		name:  "signed array index",
+		doc:   `{"foo": ["bar", "baz"]}`,
+		patch: `[{"op": "remove", "path": "/foo/+1"}]`,
+	},
+	{
+		name:  "array index with a leading zero",
+		doc:   `{"foo": ["bar", "baz"]}`,
+		patch: `[{"op": "remove", "path": "/foo/01"}]`,
+	},
+	{
+		name:  "array index out of range",
+		doc:   `{"foo": ["bar"]}`,
+		patch: `[{"op": "add", "path": "/foo/2", "value": "baz"}]`,
+	},
+	{
+		name:  "moving a value into its child",
+		doc:   `{"foo": {"bar": {}}}`,
+		patch: `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+	},
+	{
+		name:  "copying a value",
+		doc:   `{"foo": {"bar": 1}}`,
+		patch: `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "replace", "path": "/baz/bar", "value": 2}]`,
+		want:  `{"foo": {"bar": 1}, "baz": {"bar": 2}}`,
+	},
+	{
+		name:  "unknown operation",
+		doc:   `{"foo": "bar"}`,
+		patch: `[{"op": "merge", "path": "/foo", "value": "baz"}]`,
+	},
+}
+
+func TestApply(t *testing.T) {
+	for _, tt := range applyTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("Apply() = %s, want an error", got)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"replaces a member", `{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{"adds a member", `{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{"null removes a member", `{"a": "b", "b": "c"}`, `{"a": null}`, `{"b": "c"}`},
+		{"arrays are replaced", `{"a": ["b"]}`, `{"a": ["c", "d"]}`, `{"a": ["c", "d"]}`},
+		{"objects are merged", `{"a": {"b": "c", "d": "e"}}`, `{"a": {"d": null, "f": "g"}}`, `{"a": {"b": "c", "f": "g"}}`},
+		{"a scalar target becomes an object", `{"a": "b"}`, `{"a": {"c": null, "d": "e"}}`, `{"a": {"d": "e"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
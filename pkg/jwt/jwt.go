@@ -0,0 +1,286 @@
+// Package jwt signs and verifies compact JSON Web Tokens using HS256 or RS256.
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+var (
+	ErrMalformed        = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrExpired          = errors.New("token is expired")
+	ErrNotYetValid      = errors.New("token is not valid yet")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidAudience  = errors.New("invalid token audience")
+)
+
+// Audience is the "aud" claim, which may be a single string or an array.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Claims holds the registered claims the service relies on.
+type Claims struct {
+	Subject   string   `json:"sub,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Verifier checks the signature and the time, issuer and audience claims of
+// tokens. Only the algorithms whose key is set are accepted.
+type Verifier struct {
+	HMACSecret []byte
+	RSAKey     *rsa.PublicKey
+	// Issuer and Audience are checked when not empty.
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew in the exp and nbf claims.
+	Leeway time.Duration
+}
+
+// Enabled reports whether any verification key is configured.
+func (v *Verifier) Enabled() bool {
+	return v != nil && (len(v.HMACSecret) > 0 || v.RSAKey != nil)
+}
+
+// Verify checks token and returns its registered claims. When claims is not
+// nil the payload is also decoded into it, for private claims.
+func (v *Verifier) Verify(token string, claims any) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, ErrMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	switch {
+	case h.Alg == HS256 && len(v.HMACSecret) > 0:
+		mac := hmac.New(sha256.New, v.HMACSecret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, ErrInvalidSignature
+		}
+	case h.Alg == RS256 && v.RSAKey != nil:
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(v.RSAKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, ErrInvalidSignature
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedAlg, h.Alg)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	var registered Claims
+	if err := json.Unmarshal(payload, &registered); err != nil {
+		return nil, ErrMalformed
+	}
+	if claims != nil {
+		if err := json.Unmarshal(payload, claims); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+
+	if err := v.validate(&registered, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &registered, nil
+}
+
+// KeyID returns the "kid" header of token without verifying it, to select the
+// verification key among several.
+func KeyID(token string) (string, error) {
+	headerPart, _, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrMalformed
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(headerPart)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return "", ErrMalformed
+	}
+
+	return h.Kid, nil
+}
+
+func (v *Verifier) validate(claims *Claims, now time.Time) error {
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return ErrExpired
+	}
+	if claims.NotBefore != 0 && now.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrNotYetValid
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return ErrInvalidIssuer
+	}
+	if v.Audience != "" {
+		for _, audience := range claims.Audience {
+			if audience == v.Audience {
+				return nil
+			}
+		}
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// SignHS256 returns claims as a token signed with an HMAC-SHA256 secret.
+func SignHS256(claims any, secret []byte) (string, error) {
+	signingInput, err := encode(header{Alg: HS256, Typ: "JWT"}, claims)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SignRS256 returns claims as a token signed with an RSA private key. kid,
+// when not empty, names the key in the token header.
+func SignRS256(claims any, key *rsa.PrivateKey, kid string) (string, error) {
+	signingInput, err := encode(header{Alg: RS256, Typ: "JWT", Kid: kid}, claims)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encode(h header, claims any) (string, error) {
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON), nil
+}
+
+// ParseRSAPublicKeyPEM reads a PKIX or PKCS #1 public key, or the key of an
+// X.509 certificate.
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+		return nil, errors.New("certificate does not hold an RSA key")
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+		return nil, errors.New("not an RSA public key")
+	}
+}
+
+// ParseRSAPrivateKeyPEM reads a PKCS #1 or PKCS #8 private key.
+func ParseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		return rsaKey, nil
+	}
+	return nil, errors.New("not an RSA private key")
+}
@@ -0,0 +1,278 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		Subject:   "user",
+		Issuer:    "https://issuer.example",
+		Audience:  Audience{"time-tracker"},
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+}
+
+func signHS256(t *testing.T, claims any, secret []byte) string {
+	t.Helper()
+
+	token, err := SignHS256(claims, secret)
+	if err != nil {
+		t.Fatalf("SignHS256() error = %v", err)
+	}
+	return token
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier := &Verifier{HMACSecret: testSecret}
+
+	claims := validClaims()
+	got, err := verifier.Verify(signHS256(t, claims, testSecret), nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Subject != claims.Subject {
+		t.Fatalf("Verify() subject = %q, want %q", got.Subject, claims.Subject)
+	}
+
+	if _, err := verifier.Verify(signHS256(t, claims, []byte("another secret")), nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify() with another secret error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key := newTestRSAKey(t)
+	verifier := &Verifier{RSAKey: &key.PublicKey}
+
+	token, err := SignRS256(validClaims(), key, "kid-1")
+	if err != nil {
+		t.Fatalf("SignRS256() error = %v", err)
+	}
+	if _, err := verifier.Verify(token, nil); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if kid, err := KeyID(token); err != nil || kid != "kid-1" {
+		t.Fatalf("KeyID() = %q, %v, want %q", kid, err, "kid-1")
+	}
+
+	otherKey := newTestRSAKey(t)
+	forged, err := SignRS256(validClaims(), otherKey, "")
+	if err != nil {
+		t.Fatalf("SignRS256() error = %v", err)
+	}
+	if _, err := verifier.Verify(forged, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify() with another key error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+// An HS256 token whose HMAC secret is the public RSA key must not pass for a
+// token signed by the RSA key holder.
+func TestVerifyAlgorithmConfusion(t *testing.T) {
+	key := newTestRSAKey(t)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	for _, secret := range [][]byte{publicPEM, publicDER} {
+		token := signHS256(t, validClaims(), secret)
+
+		rsaOnly := &Verifier{RSAKey: &key.PublicKey}
+		if _, err := rsaOnly.Verify(token, nil); !errors.Is(err, ErrUnsupportedAlg) {
+			t.Fatalf("Verify() against an RSA key error = %v, want %v", err, ErrUnsupportedAlg)
+		}
+
+		both := &Verifier{RSAKey: &key.PublicKey, HMACSecret: testSecret}
+		if _, err := both.Verify(token, nil); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("Verify() against both keys error = %v, want %v", err, ErrInvalidSignature)
+		}
+	}
+}
+
+func TestVerifyRejectsUnsignedTokens(t *testing.T) {
+	verifier := &Verifier{HMACSecret: testSecret}
+
+	signed := signHS256(t, validClaims(), testSecret)
+	parts := strings.Split(signed, ".")
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	for _, token := range []string{
+		noneHeader + "." + parts[1] + ".",
+		parts[0] + "." + parts[1] + ".",
+		parts[0] + "." + parts[1],
+	} {
+		if _, err := verifier.Verify(token, nil); err == nil {
+			t.Fatalf("Verify(%q) succeeded, want an error", token)
+		}
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		verifier Verifier
+		modify   func(*Claims)
+		want     error
+	}{
+		{
+			name:     "valid",
+			verifier: Verifier{Issuer: "https://issuer.example", Audience: "time-tracker"},
+			modify:   func(*Claims) {},
+		},
+		{
+			name:   "missing exp",
+			modify: func(c *Claims) { c.ExpiresAt = 0 },
+			want:   ErrExpired,
+		},
+		{
+			name:   "expired",
+			modify: func(c *Claims) { c.ExpiresAt = now.Add(-time.Minute).Unix() },
+			want:   ErrExpired,
+		},
+		{
+			name:     "expired within the leeway",
+			verifier: Verifier{Leeway: time.Minute},
+			modify:   func(c *Claims) { c.ExpiresAt = now.Add(-30 * time.Second).Unix() },
+		},
+		{
+			name:     "expired beyond the leeway",
+			verifier: Verifier{Leeway: time.Minute},
+			modify:   func(c *Claims) { c.ExpiresAt = now.Add(-2 * time.Minute).Unix() },
+			want:     ErrExpired,
+		},
+		{
+			name:   "not valid yet",
+			modify: func(c *Claims) { c.NotBefore = now.Add(time.Minute).Unix() },
+			want:   ErrNotYetValid,
+		},
+		{
+			name:     "not valid yet within the leeway",
+			verifier: Verifier{Leeway: time.Minute},
+			modify:   func(c *Claims) { c.NotBefore = now.Add(30 * time.Second).Unix() },
+		},
+		{
+			name:     "issuer mismatch",
+			verifier: Verifier{Issuer: "https://other.example"},
+			modify:   func(*Claims) {},
+			want:     ErrInvalidIssuer,
+		},
+		{
+			name:     "missing issuer",
+			verifier: Verifier{Issuer: "https://issuer.example"},
+			modify:   func(c *Claims) { c.Issuer = "" },
+			want:     ErrInvalidIssuer,
+		},
+		{
+			name:     "audience mismatch",
+			verifier: Verifier{Audience: "other"},
+			modify:   func(*Claims) {},
+			want:     ErrInvalidAudience,
+		},
+		{
+			name:     "audience among several",
+			verifier: Verifier{Audience: "time-tracker"},
+			modify:   func(c *Claims) { c.Audience = Audience{"other", "time-tracker"} },
+		},
+		{
+			name:     "missing audience",
+			verifier: Verifier{Audience: "time-tracker"},
+			modify:   func(c *Claims) { c.Audience = nil },
+			want:     ErrInvalidAudience,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.modify(&claims)
+
+			verifier := tt.verifier
+			verifier.HMACSecret = testSecret
+			_, err := verifier.Verify(signHS256(t, claims, testSecret), nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPrivateClaims(t *testing.T) {
+	type private struct {
+		Claims
+		Role string `json:"role"`
+	}
+
+	verifier := &Verifier{HMACSecret: testSecret}
+	token := signHS256(t, private{Claims: validClaims(), Role: "admin"}, testSecret)
+
+	var got private
+	if _, err := verifier.Verify(token, &got); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Role != "admin" {
+		t.Fatalf("Verify() role = %q, want %q", got.Role, "admin")
+	}
+}
+
+func TestAudienceJSON(t *testing.T) {
+	var single, many Audience
+	if err := single.UnmarshalJSON([]byte(`"a"`)); err != nil || len(single) != 1 || single[0] != "a" {
+		t.Fatalf("UnmarshalJSON(string) = %v, %v", single, err)
+	}
+	if err := many.UnmarshalJSON([]byte(`["a","b"]`)); err != nil || len(many) != 2 {
+		t.Fatalf("UnmarshalJSON(array) = %v, %v", many, err)
+	}
+	if data, _ := single.MarshalJSON(); string(data) != `"a"` {
+		t.Fatalf("MarshalJSON() = %s, want %q", data, `"a"`)
+	}
+}
+
+func TestParseRSAPublicKeyPEM(t *testing.T) {
+	key := newTestRSAKey(t)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+	for _, block := range []*pem.Block{
+		{Type: "PUBLIC KEY", Bytes: pkix},
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)},
+	} {
+		parsed, err := ParseRSAPublicKeyPEM(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatalf("ParseRSAPublicKeyPEM(%s) error = %v", block.Type, err)
+		}
+		if !parsed.Equal(&key.PublicKey) {
+			t.Fatalf("ParseRSAPublicKeyPEM(%s) returned another key", block.Type)
+		}
+	}
+
+	if _, err := ParseRSAPublicKeyPEM([]byte("not PEM")); err == nil {
+		t.Fatal("ParseRSAPublicKeyPEM() succeeded on invalid input")
+	}
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"time-tracker/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier writes budget events to the application log.
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyBudgetThreshold(ctx context.Context, event models.BudgetEvent) error {
+	logrus.Warnf("Budget %q of user %s reached %d%% (%s of %s)",
+		event.Name, event.UserUUID, event.ConsumedPercent, event.Actual, event.Estimate)
+	return nil
+}
+
+// WebhookNotifier posts budget events as JSON to a configured URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (wn *WebhookNotifier) NotifyBudgetThreshold(ctx context.Context, event models.BudgetEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package peopleinfo is the client of the external people info API, which
+// returns the personal details registered for a passport:
+//
+//	GET /info?passportSerie=1234&passportNumber=567890
+//
+// Calls time out, are retried on network errors and server errors, and stop
+// for a while behind a circuit breaker once the API keeps failing.
+package peopleinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"time-tracker/internal/models"
+)
+
+// maxResponseSize bounds the response bodies read from the API.
+const maxResponseSize = 1 << 20
+
+var (
+	ErrCircuitOpen     = errors.New("people info API circuit is open")
+	ErrInvalidResponse = errors.New("invalid people info response")
+)
+
+type Config struct {
+	// BaseURL is the URL the /info path is appended to.
+	BaseURL string
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// MaxRetries is how many times a failed call is attempted again.
+	MaxRetries int
+	// RetryDelay is the base of the exponential backoff between attempts.
+	RetryDelay time.Duration
+	// FailureThreshold is the number of failed calls in a row that opens the
+	// circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a call is let
+	// through to probe the API.
+	OpenTimeout time.Duration
+}
+
+// Client looks people up in the people info API. It is safe for concurrent
+// use.
+type Client struct {
+	config  Config
+	client  *http.Client
+	breaker *breaker
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		client: &http.Client{},
+		breaker: &breaker{
+			threshold:   config.FailureThreshold,
+			openTimeout: config.OpenTimeout,
+		},
+	}
+}
+
+type person struct {
+	Surname    string  `json:"surname"`
+	Name       string  `json:"name"`
+	Patronymic *string `json:"patronymic"`
+	Address    string  `json:"address"`
+}
+
+// retryableError is a failure worth another attempt.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// GetPersonInfo returns the details registered for a passport, or nil when the
+// API does not know it.
+func (c *Client) GetPersonInfo(ctx context.Context, passportSerie, passportNumber string) (*models.PersonInfo, error) {
+	if !c.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	info, err := c.getWithRetries(ctx, passportSerie, passportNumber)
+
+	// Requests canceled by the caller say nothing about the API.
+	if ctx.Err() == nil {
+		c.breaker.record(err == nil || !isRetryable(err))
+	} else {
+		c.breaker.release()
+	}
+	return info, err
+}
+
+func (c *Client) getWithRetries(ctx context.Context, passportSerie, passportNumber string) (*models.PersonInfo, error) {
+	delay := c.config.RetryDelay
+	for attempt := 0; ; attempt++ {
+		info, err := c.get(ctx, passportSerie, passportNumber)
+		if err == nil || !isRetryable(err) || attempt >= c.config.MaxRetries {
+			return info, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) get(ctx context.Context, passportSerie, passportNumber string) (*models.PersonInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	query := url.Values{
+		"passportSerie":  {passportSerie},
+		"passportNumber": {passportNumber},
+	}
+	endpoint := strings.TrimSuffix(c.config.BaseURL, "/") + "/info?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, &retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, &retryableError{fmt.Errorf("people info API responded with status %d", resp.StatusCode)}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("people info API responded with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, &retryableError{err}
+	}
+
+	var p person
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if p.Surname == "" || p.Name == "" || p.Address == "" {
+		return nil, fmt.Errorf("%w: surname, name and address are required", ErrInvalidResponse)
+	}
+	if p.Patronymic != nil && *p.Patronymic == "" {
+		p.Patronymic = nil
+	}
+
+	return &models.PersonInfo{
+		Surname:    p.Surname,
+		Name:       p.Name,
+		Patronymic: p.Patronymic,
+		Address:    p.Address,
+	}, nil
+}
+
+func isRetryable(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
+// breaker is a circuit breaker. It opens after threshold failed calls in a
+// row and rejects calls until openTimeout has passed. Then it lets a single
+// call through, whose outcome closes the circuit or opens it again.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.openTimeout {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// release ends a call without counting its outcome.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
@@ -0,0 +1,273 @@
+package peopleinfo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stub is a local people info API answering with the statuses of respond.
+type stub struct {
+	*httptest.Server
+	calls atomic.Int32
+}
+
+func newStub(t *testing.T, respond func(w http.ResponseWriter, r *http.Request, call int)) *stub {
+	t.Helper()
+
+	s := &stub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, int(s.calls.Add(1)))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func found(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"surname": "Ivanov", "name": "Ivan", "patronymic": "", "address": "ul. Lenina, d. 5"}`))
+}
+
+func newTestClient(baseURL string, config Config) *Client {
+	config.BaseURL = baseURL
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = time.Millisecond
+	}
+	return NewClient(config)
+}
+
+func TestGetPersonInfo(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if r.URL.Path != "/info" || r.URL.Query().Get("passportSerie") != "1234" || r.URL.Query().Get("passportNumber") != "567890" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		found(w)
+	})
+	client := newTestClient(s.URL+"/", Config{})
+
+	info, err := client.GetPersonInfo(context.Background(), "1234", "567890")
+	if err != nil {
+		t.Fatalf("GetPersonInfo() error = %v", err)
+	}
+	if info == nil || info.Surname != "Ivanov" || info.Name != "Ivan" || info.Address != "ul. Lenina, d. 5" {
+		t.Fatalf("GetPersonInfo() = %+v", info)
+	}
+	if info.Patronymic != nil {
+		t.Fatalf("GetPersonInfo() patronymic = %q, want nil for an empty one", *info.Patronymic)
+	}
+}
+
+func TestGetPersonInfoNotFound(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		http.NotFound(w, r)
+	})
+	client := newTestClient(s.URL, Config{MaxRetries: 2, FailureThreshold: 1})
+
+	for i := 0; i < 2; i++ {
+		info, err := client.GetPersonInfo(context.Background(), "1234", "567890")
+		if err != nil || info != nil {
+			t.Fatalf("GetPersonInfo() = %+v, %v, want nil, nil", info, err)
+		}
+	}
+	// Unknown passports are answers, neither retried nor counted as failures.
+	if calls := s.calls.Load(); calls != 2 {
+		t.Fatalf("API called %d times, want 2", calls)
+	}
+}
+
+func TestGetPersonInfoRetries(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		switch call {
+		case 1:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case 2:
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		default:
+			found(w)
+		}
+	})
+	client := newTestClient(s.URL, Config{MaxRetries: 2})
+
+	info, err := client.GetPersonInfo(context.Background(), "1234", "567890")
+	if err != nil || info == nil {
+		t.Fatalf("GetPersonInfo() = %+v, %v, want the person", info, err)
+	}
+	if calls := s.calls.Load(); calls != 3 {
+		t.Fatalf("API called %d times, want 3", calls)
+	}
+}
+
+func TestGetPersonInfoGivesUpAfterRetries(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	})
+	client := newTestClient(s.URL, Config{MaxRetries: 2})
+
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil {
+		t.Fatal("GetPersonInfo() succeeded, want an error")
+	}
+	if calls := s.calls.Load(); calls != 3 {
+		t.Fatalf("API called %d times, want 3", calls)
+	}
+}
+
+func TestGetPersonInfoDoesNotRetryClientErrors(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"surname": "Ivanov"}`))
+	})
+	client := newTestClient(s.URL, Config{MaxRetries: 2})
+
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil {
+		t.Fatal("GetPersonInfo() succeeded on 400, want an error")
+	}
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("GetPersonInfo() error = %v, want %v", err, ErrInvalidResponse)
+	}
+	if calls := s.calls.Load(); calls != 2 {
+		t.Fatalf("API called %d times, want 2", calls)
+	}
+}
+
+func TestGetPersonInfoTimeout(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		found(w)
+	})
+	client := newTestClient(s.URL, Config{Timeout: 20 * time.Millisecond, MaxRetries: 1})
+
+	info, err := client.GetPersonInfo(context.Background(), "1234", "567890")
+	if err != nil || info == nil {
+		t.Fatalf("GetPersonInfo() = %+v, %v, want the person on the second attempt", info, err)
+	}
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if failing.Load() {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		found(w)
+	})
+	client := newTestClient(s.URL, Config{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("GetPersonInfo() error = %v, want the API error", err)
+		}
+	}
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetPersonInfo() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if calls := s.calls.Load(); calls != 2 {
+		t.Fatalf("API called %d times, want 2 before the circuit opened", calls)
+	}
+
+	// A failed probe opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe error = %v, want the API error", err)
+	}
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetPersonInfo() after a failed probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// A successful probe closes it.
+	failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err != nil {
+			t.Fatalf("GetPersonInfo() after a successful probe error = %v", err)
+		}
+	}
+}
+
+func TestBreakerLetsASingleProbeThrough(t *testing.T) {
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		close(probing)
+		<-release
+		found(w)
+	})
+	client := newTestClient(s.URL, Config{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil {
+		t.Fatal("GetPersonInfo() succeeded, want the API error")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	probe := make(chan error, 1)
+	go func() {
+		_, err := client.GetPersonInfo(context.Background(), "1234", "567890")
+		probe <- err
+	}()
+	<-probing
+
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetPersonInfo() during the probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	close(release)
+	if err := <-probe; err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if calls := s.calls.Load(); calls != 2 {
+		t.Fatalf("API called %d times, want 2", calls)
+	}
+}
+
+func TestBreakerReleasesACanceledProbe(t *testing.T) {
+	s := newStub(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 2 {
+			<-r.Context().Done()
+			return
+		}
+		if call == 1 {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		found(w)
+	})
+	client := newTestClient(s.URL, Config{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err == nil {
+		t.Fatal("GetPersonInfo() succeeded, want the API error")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetPersonInfo(ctx, "1234", "567890"); err == nil {
+		t.Fatal("canceled probe succeeded, want an error")
+	}
+
+	// The canceled probe says nothing about the API, so the next call probes
+	// again instead of waiting behind it.
+	if _, err := client.GetPersonInfo(context.Background(), "1234", "567890"); err != nil {
+		t.Fatalf("GetPersonInfo() after a canceled probe error = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"time"
 	db "time-tracker/internal/db/sqlc"
 	"time-tracker/internal/models"
@@ -9,7 +10,9 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-func ConvertDBUserToModelsUser(user db.User) (*models.User, error) {
+// ConvertDBUserToModelsUser converts a user row whose passport number was
+// decrypted by the caller.
+func ConvertDBUserToModelsUser(user db.User, passportNumber string) (*models.User, error) {
 	var uuid uuid.UUID
 	err := uuid.UnmarshalBinary(user.Uuid.Bytes[:])
 	if err != nil {
@@ -22,14 +25,22 @@ func ConvertDBUserToModelsUser(user db.User) (*models.User, error) {
 	}
 
 	return &models.User{
-		UUID:           uuid,
-		PassportNumber: user.PassportNumber,
-		Surname:        user.Surname,
-		Name:           user.Name,
-		Patronymic:     patronymic,
-		Address:        user.Address,
-		CreatedAt:      user.CreatedAt.Time,
-		UpdatedAt:      user.UpdatedAt.Time,
+		UUID:             uuid,
+		PassportNumber:   passportNumber,
+		Surname:          user.Surname,
+		Name:             user.Name,
+		Patronymic:       patronymic,
+		Address:          user.Address,
+		CreatedAt:        user.CreatedAt.Time,
+		UpdatedAt:        user.UpdatedAt.Time,
+		Version:          user.Version,
+		Role:             user.Role,
+		ManagerUUID:      FromPgUUID(user.ManagerUuid),
+		OrganizationUUID: user.OrganizationUuid.Bytes,
+		Email:            FromPgText(user.Email),
+		Permissions:      user.Permissions,
+		DeletedAt:        FromPgTimestamptz(user.DeletedAt),
+		AnonymizedAt:     FromPgTimestamptz(user.AnonymizedAt),
 	}, nil
 }
 
@@ -52,15 +63,38 @@ func ConvertDBTaskToModelsTask(dbTask db.Task) (*models.Task, error) {
 
 	}
 
+	var plannedEndTime *time.Time
+	if dbTask.PlannedEndTime.Valid {
+		plannedEndTime = &dbTask.PlannedEndTime.Time
+	}
+
 	return &models.Task{
-		UUID:      taskUUID,
-		UserUUID:  userUUID,
-		Name:      dbTask.Name,
-		StartTime: dbTask.StartTime.Time,
-		EndTime:   endTime,
+		UUID:           taskUUID,
+		UserUUID:       userUUID,
+		Name:           dbTask.Name,
+		Description:    FromPgText(dbTask.Description),
+		Notes:          FromPgText(dbTask.Notes),
+		Mode:           dbTask.Mode,
+		StartTime:      dbTask.StartTime.Time,
+		EndTime:        endTime,
+		PlannedEndTime: plannedEndTime,
 	}, nil
 }
 
+func ToPgInt4(i *int) pgtype.Int4 {
+	if i != nil {
+		return pgtype.Int4{Int32: int32(*i), Valid: true}
+	}
+	return pgtype.Int4{Valid: false}
+}
+
+func ToPgBool(b *bool) pgtype.Bool {
+	if b != nil {
+		return pgtype.Bool{Bool: *b, Valid: true}
+	}
+	return pgtype.Bool{Valid: false}
+}
+
 func ToPgText(s *string) pgtype.Text {
 	if s != nil {
 		return pgtype.Text{String: *s, Valid: true}
@@ -68,6 +102,46 @@ func ToPgText(s *string) pgtype.Text {
 	return pgtype.Text{Valid: false}
 }
 
+func FromPgText(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func FromPgTimestamptz(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func FromPgUUID(u pgtype.UUID) *uuid.UUID {
+	if !u.Valid {
+		return nil
+	}
+	id := uuid.UUID(u.Bytes)
+	return &id
+}
+
+func ToPgUUID(u *uuid.UUID) pgtype.UUID {
+	if u != nil {
+		return pgtype.UUID{Bytes: *u, Valid: true}
+	}
+	return pgtype.UUID{Valid: false}
+}
+
+// FormatDuration renders seconds in the same "X hours Y minutes" form the
+// reporting queries produce.
+func FormatDuration(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%d hours %d minutes", sign, seconds/3600, seconds/60%60)
+}
+
 func ConvertDBTaskHistoryToModelsTaskHistory(dbTask db.TaskHistory) (*models.TaskHistory, error) {
 	var modelsTask models.TaskHistory
 
@@ -85,6 +159,233 @@ func ConvertDBTaskHistoryToModelsTaskHistory(dbTask db.TaskHistory) (*models.Tas
 	}
 
 	modelsTask.Name = dbTask.Name
+	modelsTask.Description = FromPgText(dbTask.Description)
+	modelsTask.Notes = FromPgText(dbTask.Notes)
 
 	return &modelsTask, nil
 }
+
+func ConvertDBTaskHistoryEntryToModelsTaskHistory(entry db.GetTaskHistoryByUUIDRow) (*models.TaskHistory, error) {
+	var entryUUID uuid.UUID
+	err := entryUUID.UnmarshalBinary(entry.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var userUUID uuid.UUID
+	err = userUUID.UnmarshalBinary(entry.UserUuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskHistory{
+		Uuid:        entryUUID,
+		UserUuid:    userUUID,
+		Name:        entry.Name,
+		Description: FromPgText(entry.Description),
+		Notes:       FromPgText(entry.Notes),
+		StartTime:   entry.StartTime.Time,
+		EndTime:     entry.EndTime.Time,
+		Mode:        entry.Mode,
+		Version:     entry.Version,
+	}, nil
+}
+
+func ConvertDBTaskBudgetUsageToModelsTaskBudget(usage db.GetTaskBudgetsUsageRow) (*models.TaskBudget, error) {
+	var budgetUUID uuid.UUID
+	err := budgetUUID.UnmarshalBinary(usage.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	estimateSeconds := int64(usage.EstimateMinutes) * 60
+
+	return &models.TaskBudget{
+		UUID:            budgetUUID,
+		Name:            usage.Name,
+		Estimate:        FormatDuration(estimateSeconds),
+		Actual:          FormatDuration(usage.ActualSeconds),
+		Remaining:       FormatDuration(estimateSeconds - usage.ActualSeconds),
+		ConsumedPercent: int(usage.ActualSeconds * 100 / estimateSeconds),
+	}, nil
+}
+
+func ConvertDBPomodoroSettingToModelsPomodoroSettings(settings db.PomodoroSetting) *models.PomodoroSettings {
+	return &models.PomodoroSettings{
+		FocusMinutes:          int(settings.FocusMinutes),
+		ShortBreakMinutes:     int(settings.ShortBreakMinutes),
+		LongBreakMinutes:      int(settings.LongBreakMinutes),
+		CyclesBeforeLongBreak: int(settings.CyclesBeforeLongBreak),
+		AutoStartBreak:        settings.AutoStartBreak,
+	}
+}
+
+func ConvertDBWorkScheduleToModelsWorkSchedule(schedule db.WorkSchedule) (*models.WorkSchedule, error) {
+	var scheduleUUID uuid.UUID
+	err := scheduleUUID.UnmarshalBinary(schedule.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	minutes := []int32{
+		schedule.MondayMinutes,
+		schedule.TuesdayMinutes,
+		schedule.WednesdayMinutes,
+		schedule.ThursdayMinutes,
+		schedule.FridayMinutes,
+		schedule.SaturdayMinutes,
+		schedule.SundayMinutes,
+	}
+	var weeklyMinutes int32
+	for _, m := range minutes {
+		weeklyMinutes += m
+	}
+
+	return &models.WorkSchedule{
+		UUID:          scheduleUUID,
+		EffectiveFrom: schedule.EffectiveFrom.Time.Format(time.DateOnly),
+		Monday:        float64(schedule.MondayMinutes) / 60,
+		Tuesday:       float64(schedule.TuesdayMinutes) / 60,
+		Wednesday:     float64(schedule.WednesdayMinutes) / 60,
+		Thursday:      float64(schedule.ThursdayMinutes) / 60,
+		Friday:        float64(schedule.FridayMinutes) / 60,
+		Saturday:      float64(schedule.SaturdayMinutes) / 60,
+		Sunday:        float64(schedule.SundayMinutes) / 60,
+		WeeklyHours:   float64(weeklyMinutes) / 60,
+	}, nil
+}
+
+func ConvertDBAbsenceToModelsAbsence(absence db.Absence) (*models.Absence, error) {
+	var absenceUUID uuid.UUID
+	err := absenceUUID.UnmarshalBinary(absence.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var note *string
+	if absence.Note.Valid {
+		note = &absence.Note.String
+	}
+
+	return &models.Absence{
+		UUID:         absenceUUID,
+		Type:         absence.Type,
+		StartDate:    absence.StartDate.Time.Format(time.DateOnly),
+		EndDate:      absence.EndDate.Time.Format(time.DateOnly),
+		HalfDayStart: absence.HalfDayStart,
+		HalfDayEnd:   absence.HalfDayEnd,
+		Note:         note,
+	}, nil
+}
+
+func ConvertDBHolidayCalendarToModelsHolidayCalendar(calendar db.HolidayCalendar) (*models.HolidayCalendar, error) {
+	var calendarUUID uuid.UUID
+	err := calendarUUID.UnmarshalBinary(calendar.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var region *string
+	if calendar.Region.Valid {
+		region = &calendar.Region.String
+	}
+
+	return &models.HolidayCalendar{
+		UUID:   calendarUUID,
+		Name:   calendar.Name,
+		Region: region,
+	}, nil
+}
+
+func ConvertDBHolidayToModelsHoliday(holiday db.Holiday) models.Holiday {
+	return models.Holiday{
+		Date: holiday.Day.Time.Format(time.DateOnly),
+		Name: holiday.Name,
+	}
+}
+
+func ConvertDBTaskSearchRowToModelsTaskSearchResult(row db.SearchTaskHistoryRow) (*models.TaskSearchResult, error) {
+	var entryUUID uuid.UUID
+	err := entryUUID.UnmarshalBinary(row.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var userUUID uuid.UUID
+	err = userUUID.UnmarshalBinary(row.UserUuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskSearchResult{
+		UUID:          entryUUID,
+		UserUUID:      userUUID,
+		Name:          row.Name,
+		Description:   FromPgText(row.Description),
+		Notes:         FromPgText(row.Notes),
+		StartTime:     row.StartTime.Time,
+		EndTime:       row.EndTime.Time,
+		Duration:      FormatDuration(int64(row.EndTime.Time.Sub(row.StartTime.Time).Seconds())),
+		Rank:          float64(row.Rank),
+		NameHighlight: row.NameHighlight,
+		TextHighlight: row.TextHighlight,
+	}, nil
+}
+
+func ConvertDBAPIKeyToModelsAPIKey(key db.ApiKey) (*models.APIKey, error) {
+	var keyUUID uuid.UUID
+	err := keyUUID.UnmarshalBinary(key.Uuid.Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.APIKey{
+		UUID:       keyUUID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		CreatedAt:  key.CreatedAt.Time,
+		ExpiresAt:  FromPgTimestamptz(key.ExpiresAt),
+		LastUsedAt: FromPgTimestamptz(key.LastUsedAt),
+		RevokedAt:  FromPgTimestamptz(key.RevokedAt),
+	}, nil
+}
+
+func ConvertDBPIIAccessToModelsPIIAccess(access db.PiiAccessLog) models.PIIAccess {
+	return models.PIIAccess{
+		UUID:          access.Uuid.Bytes,
+		Actor:         access.Actor,
+		ActorUserUUID: FromPgUUID(access.ActorUserUuid),
+		APIKeyUUID:    FromPgUUID(access.ApiKeyUuid),
+		UserUUID:      access.UserUuid.Bytes,
+		Field:         access.Field,
+		Operation:     access.Operation,
+		Reason:        FromPgText(access.Reason),
+		AccessedAt:    access.AccessedAt.Time,
+	}
+}
+
+func ConvertDBAuditLogToModelsAuditEntry(entry db.AuditLog) models.AuditEntry {
+	return models.AuditEntry{
+		UUID:          entry.Uuid.Bytes,
+		Actor:         entry.Actor,
+		ActorUserUUID: FromPgUUID(entry.ActorUserUuid),
+		APIKeyUUID:    FromPgUUID(entry.ApiKeyUuid),
+		RequestID:     FromPgText(entry.RequestID),
+		SourceIP:      FromPgText(entry.SourceIp),
+		Entity:        entry.Entity,
+		EntityUUID:    entry.EntityUuid.Bytes,
+		UserUUID:      entry.UserUuid.Bytes,
+		Action:        entry.Action,
+		Before:        entry.Before,
+		After:         entry.After,
+		CreatedAt:     entry.CreatedAt.Time,
+	}
+}
+
+func ConvertDBUserIdentityToModelsUserIdentity(identity db.UserIdentity) models.UserIdentity {
+	return models.UserIdentity{
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		CreatedAt: identity.CreatedAt.Time,
+	}
+}